@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+)
+
+func printAddUsage() {
+	fmt.Println("Usage: vasgotools.exe add <item> [<name>] [--path <module>] [--kind <kind>] [force]")
+	fmt.Println()
+	fmt.Println("Items:")
+	fmt.Println("  docker       Add a Dockerfile, .dockerignore and .devcontainer/devcontainer.json to an application")
+	fmt.Println("  pkg <path>   Add a package (doc.go, starter code, tests) in the folder <path> of the module")
+	fmt.Println("  cmd <name>   Add a command in cmd/<name> (main.go of --kind basic|cli|service|worker) and")
+	fmt.Println("               build it with the cross-build scripts")
+}
+
+// addCommand adds an item to an existing module.
+func addCommand(args []string) {
+	if len(args) < 1 {
+		printAddUsage()
+		os.Exit(1)
+	}
+	item := args[0]
+
+	// Define a flag set for the "add" command
+	fs := flag.NewFlagSet("add "+item, flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module (defaults to current working directory)")
+	kind := fs.String("kind", appKindBasic, "Kind of command: basic, cli, service or worker (only for cmd)")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	force := slices.Contains(positional, "force")
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	switch item {
+	case "docker":
+		addDockerCommand(*folderPath, force)
+	case "pkg":
+		if len(positional) < 1 {
+			fmt.Println("Error: Package path is required.")
+			printAddUsage()
+			os.Exit(1)
+		}
+		addPackageCommand(*folderPath, positional[0], force)
+	case "cmd":
+		if len(positional) < 1 {
+			fmt.Println("Error: Command name is required.")
+			printAddUsage()
+			os.Exit(1)
+		}
+		addCmdCommand(*folderPath, positional[0], *kind, force)
+	default:
+		fmt.Printf("Unknown item: %s\n", item)
+		printAddUsage()
+		os.Exit(1)
+	}
+}
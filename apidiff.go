@@ -0,0 +1,443 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// apiEntry is an element of the exported API of a module, e.g. a function, a struct field or a method.
+type apiEntry struct {
+	// description is compared between the versions, e.g. the signature of a function
+	description string
+	// addingBreaks is set for methods of interfaces that can be implemented outside the package
+	addingBreaks bool
+}
+
+// apiChange is a difference between two versions of the exported API.
+type apiChange struct {
+	name     string
+	message  string
+	breaking bool
+}
+
+func apidiffCommand(args []string) {
+	// Define a flag set for the "apidiff" command
+	fs := flag.NewFlagSet("apidiff", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module (defaults to current working directory)")
+	from := fs.String("from", "", "Git tag or commit of the old version (default: the last release tag of the module)")
+	version := fs.String("version", "", "Version of the new release to check (default: the release tag at HEAD, if any)")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	mod, err := readGoMod(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	prefix, err := moduleTagPrefix(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	// Determine the old version and the version of the new release
+	if _, ok := parseSemver(*version); *version != "" && !ok {
+		fmt.Printf("Error: invalid version '%s' (use vX.Y.Z)\n", *version)
+		os.Exit(1)
+	}
+	base, released, err := apidiffVersions(*folderPath, prefix, *version)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	ref := *from
+	if ref == "" {
+		if base == "" {
+			fmt.Println("No release tag found => nothing to compare (the first release is v0.1.0).")
+			return
+		}
+		ref = prefix + base
+	}
+
+	oldAPI, err := loadAPIAtRef(*folderPath, prefix, ref, mod.Module.Path)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	newAPI, err := loadModuleAPI(*folderPath, mod.Module.Path)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	changes := compareAPI(oldAPI, newAPI)
+	bump := apiBump(changes)
+	printAPIChanges(ref, changes)
+
+	fmt.Println()
+	if base == "" {
+		fmt.Printf("Recommended bump: %s\n", bump)
+	} else {
+		next, err := nextVersion(base, bump)
+		if err != nil {
+			v, _ := parseSemver(base)
+			next = fmt.Sprintf("v%d.0.0 (needs the module path suffix /v%d)", v.major+1, v.major+1)
+		}
+		fmt.Printf("Recommended bump: %s => %s\n", bump, next)
+	}
+	if released == "" || base == "" {
+		return
+	}
+
+	// Check the version of the release against the API changes
+	if !isSufficientBump(base, released, bump) {
+		if bump == bumpMajor {
+			fmt.Printf("Error: release %s contains breaking changes but is no major version bump of %s (minor before v1.0.0).\n", released, base)
+			os.Exit(1)
+		}
+		fmt.Printf("Warning: release %s contains new API elements and should be a minor version bump of %s.\n", released, base)
+	}
+}
+
+// apidiffVersions returns the version of the release to check (the given version or the release tag
+// at HEAD, if any) and the version of the release before.
+func apidiffVersions(folderPath, prefix, version string) (base, released string, err error) {
+	versions, err := moduleVersions(folderPath)
+	if err != nil || len(versions) == 0 {
+		return "", version, err
+	}
+
+	released = version
+	if released == "" {
+		headTags, err := gitOutput(folderPath, "tag", "--points-at", "HEAD", "--list", prefix+"v*")
+		if err != nil {
+			return "", "", err
+		}
+		if latest := versions[len(versions)-1]; slices.Contains(strings.Fields(headTags), prefix+latest) {
+			released = latest
+		}
+	}
+
+	for _, v := range versions {
+		if released == "" || compareSemver(v, released) < 0 {
+			base = v
+		}
+	}
+	return base, released, nil
+}
+
+// isSufficientBump reports whether the step from base to released is at least the required bump.
+// Before v1.0.0 a minor bump is sufficient for breaking changes.
+func isSufficientBump(base, released, bump string) bool {
+	b, okBase := parseSemver(base)
+	r, okReleased := parseSemver(released)
+	if !okBase || !okReleased || compareSemver(released, base) <= 0 {
+		return false
+	}
+	if bump == bumpMajor && b.major == 0 {
+		bump = bumpMinor
+	}
+
+	switch bump {
+	case bumpMajor:
+		return r.major > b.major
+	case bumpMinor:
+		return r.major > b.major || r.minor > b.minor
+	default:
+		return true
+	}
+}
+
+// loadAPIAtRef loads the exported API of the module at a Git tag or commit, using a temporary worktree.
+func loadAPIAtRef(folderPath, prefix, ref, modulePath string) (map[string]apiEntry, error) {
+	worktree, err := os.MkdirTemp("", "vasgotools-apidiff-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary folder: %w", err)
+	}
+	defer os.RemoveAll(worktree)
+
+	_, err = gitOutput(folderPath, "worktree", "add", "--detach", worktree, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = gitOutput(folderPath, "worktree", "remove", "--force", worktree)
+	}()
+
+	return loadModuleAPI(filepath.Join(worktree, filepath.FromSlash(prefix)), modulePath)
+}
+
+// loadModuleAPI type-checks the packages of the module in folderPath (without internal packages,
+// commands and tests) and returns their exported API.
+func loadModuleAPI(folderPath, modulePath string) (map[string]apiEntry, error) {
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	api := make(map[string]apiEntry)
+
+	err := filepath.WalkDir(folderPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != folderPath {
+			if name == "vendor" || name == "testdata" || name == "internal" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if fileExists(filepath.Join(path, "go.mod")) {
+				return filepath.SkipDir // nested module
+			}
+		}
+
+		pkg, err := build.ImportDir(path, 0)
+		var noGoError *build.NoGoError
+		if errors.As(err, &noGoError) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading package %s: %w", path, err)
+		}
+		if pkg.Name == "main" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		importPath := modulePath
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		return addPackageAPI(api, fset, imp, importPath, path, pkg.GoFiles)
+	})
+	return api, err
+}
+
+// addPackageAPI type-checks a package and adds its exported API.
+func addPackageAPI(api map[string]apiEntry, fset *token.FileSet, imp types.Importer, importPath, dir string, fileNames []string) error {
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", fileName, err)
+		}
+		files = append(files, file)
+	}
+
+	var typeErrors []error
+	conf := types.Config{Importer: imp, Error: func(err error) { typeErrors = append(typeErrors, err) }}
+	pkg, _ := conf.Check(importPath, fset, files, nil)
+	if len(typeErrors) > 0 {
+		// The API is still compared, types depending on the error are reported as invalid
+		fmt.Printf("Warning: %d type error(s) in %s, first: %v\n", len(typeErrors), importPath, typeErrors[0])
+	}
+
+	// Parameter and result names are not part of the API: renaming them is compatible
+	qualifier := func(other *types.Package) string { return other.Path() }
+	typeString := func(t types.Type) string { return types.TypeString(unnamedType(t), qualifier) }
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		key := importPath + "." + name
+
+		switch obj := obj.(type) {
+		case *types.Func:
+			api[key] = apiEntry{description: typeString(obj.Type())}
+		case *types.Const:
+			api[key] = apiEntry{description: "const " + typeString(obj.Type())}
+		case *types.Var:
+			api[key] = apiEntry{description: "var " + typeString(obj.Type())}
+		case *types.TypeName:
+			addTypeAPI(api, key, obj, typeString)
+		}
+	}
+	return nil
+}
+
+// addTypeAPI adds a type with its exported fields and methods.
+func addTypeAPI(api map[string]apiEntry, key string, obj *types.TypeName, typeString func(types.Type) string) {
+	if obj.IsAlias() {
+		api[key] = apiEntry{description: "type = " + typeString(obj.Type())}
+		return
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return
+	}
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		api[key] = apiEntry{description: "type struct"}
+		for i := range underlying.NumFields() {
+			field := underlying.Field(i)
+			if field.Exported() {
+				api[key+"."+field.Name()] = apiEntry{description: "field " + typeString(field.Type())}
+			}
+		}
+	case *types.Interface:
+		api[key] = apiEntry{description: "type interface"}
+		// Interfaces with unexported methods cannot be implemented outside the package
+		sealed := false
+		for i := range underlying.NumMethods() {
+			if !underlying.Method(i).Exported() {
+				sealed = true
+			}
+		}
+		for i := range underlying.NumMethods() {
+			method := underlying.Method(i)
+			if method.Exported() {
+				api[key+"."+method.Name()] = apiEntry{description: typeString(method.Type()), addingBreaks: !sealed}
+			}
+		}
+		return
+	default:
+		api[key] = apiEntry{description: "type " + typeString(underlying)}
+	}
+
+	// The method set of the pointer contains the methods of value and pointer receivers
+	methods := types.NewMethodSet(types.NewPointer(named))
+	for i := range methods.Len() {
+		method := methods.At(i).Obj()
+		if method.Exported() {
+			api[key+"."+method.Name()] = apiEntry{description: typeString(method.Type())}
+		}
+	}
+}
+
+// unnamedType returns t with the parameter and result names removed from the function types it
+// consists of, e.g. "func(count int) (n int)" becomes "func(int) int".
+func unnamedType(t types.Type) types.Type {
+	switch t := t.(type) {
+	case *types.Signature:
+		// The type parameters are redeclared, they belong to a single signature
+		typeParams := make([]*types.TypeParam, 0, t.TypeParams().Len())
+		for i := range t.TypeParams().Len() {
+			obj := t.TypeParams().At(i).Obj()
+			typeName := types.NewTypeName(obj.Pos(), obj.Pkg(), obj.Name(), nil)
+			typeParams = append(typeParams, types.NewTypeParam(typeName, t.TypeParams().At(i).Constraint()))
+		}
+		return types.NewSignatureType(nil, nil, typeParams, unnamedTuple(t.Params()), unnamedTuple(t.Results()), t.Variadic())
+	case *types.Pointer:
+		return types.NewPointer(unnamedType(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unnamedType(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unnamedType(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(unnamedType(t.Key()), unnamedType(t.Elem()))
+	case *types.Chan:
+		return types.NewChan(t.Dir(), unnamedType(t.Elem()))
+	}
+	return t
+}
+
+// unnamedTuple returns the parameters or results without names (see unnamedType).
+func unnamedTuple(tuple *types.Tuple) *types.Tuple {
+	vars := make([]*types.Var, 0, tuple.Len())
+	for i := range tuple.Len() {
+		v := tuple.At(i)
+		vars = append(vars, types.NewParam(v.Pos(), v.Pkg(), "", unnamedType(v.Type())))
+	}
+	return types.NewTuple(vars...)
+}
+
+// compareAPI returns the changes from the old to the new API, sorted by name.
+func compareAPI(oldAPI, newAPI map[string]apiEntry) []apiChange {
+	var changes []apiChange
+	for name, oldEntry := range oldAPI {
+		newEntry, ok := newAPI[name]
+		switch {
+		case !ok:
+			changes = append(changes, apiChange{name: name, message: "removed", breaking: true})
+		case newEntry.description != oldEntry.description:
+			changes = append(changes, apiChange{
+				name:     name,
+				message:  fmt.Sprintf("changed from %s to %s", oldEntry.description, newEntry.description),
+				breaking: true,
+			})
+		}
+	}
+	for name, newEntry := range newAPI {
+		if _, ok := oldAPI[name]; ok {
+			continue
+		}
+		// Adding a method to an existing interface breaks its implementations
+		typeName := name[:strings.LastIndex(name, ".")]
+		if _, existed := oldAPI[typeName]; newEntry.addingBreaks && existed {
+			changes = append(changes, apiChange{name: name, message: "added to interface", breaking: true})
+		} else {
+			changes = append(changes, apiChange{name: name, message: "added"})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b apiChange) int { return strings.Compare(a.name, b.name) })
+	return changes
+}
+
+// apiBump returns the bump required by the changes: major for breaking changes, minor for additions
+// and patch otherwise.
+func apiBump(changes []apiChange) string {
+	bump := bumpPatch
+	for _, change := range changes {
+		if change.breaking {
+			return bumpMajor
+		}
+		bump = bumpMinor
+	}
+	return bump
+}
+
+// printAPIChanges prints the breaking and the compatible changes.
+func printAPIChanges(ref string, changes []apiChange) {
+	if len(changes) == 0 {
+		fmt.Printf("No changes of the exported API since %s.\n", ref)
+		return
+	}
+
+	fmt.Printf("Changes of the exported API since %s:\n", ref)
+	for _, breaking := range []bool{true, false} {
+		title := "Compatible changes:"
+		if breaking {
+			title = "Breaking changes:"
+		}
+		printed := false
+		for _, change := range changes {
+			if change.breaking != breaking {
+				continue
+			}
+			if !printed {
+				fmt.Println(title)
+				printed = true
+			}
+			fmt.Printf("  %s: %s\n", change.name, change.message)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadTestAPI writes source as the only file of a module and returns its exported API.
+func loadTestAPI(t *testing.T, source string) map[string]apiEntry {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "m.go"), []byte("package m\n\n"+source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	api, err := loadModuleAPI(dir, "example.com/m")
+	if err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+	return api
+}
+
+func TestCompareAPI(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldSource    string
+		newSource    string
+		wantBreaking []string
+		wantAdded    []string
+		wantBump     string
+	}{
+		{
+			name:      "parameter renamed",
+			oldSource: "func Scale(x float64, factor int) float64 { return x }",
+			newSource: "func Scale(value float64, n int) float64 { return value }",
+			wantBump:  bumpPatch,
+		},
+		{
+			name:      "result renamed",
+			oldSource: "func Split(s string) (head, tail string) { return }",
+			newSource: "func Split(s string) (first, rest string) { return }",
+			wantBump:  bumpPatch,
+		},
+		{
+			name:      "named result removed",
+			oldSource: "func Count() (n int) { return }",
+			newSource: "func Count() int { return 0 }",
+			wantBump:  bumpPatch,
+		},
+		{
+			name:      "method and function type parameters renamed",
+			oldSource: "type Filter struct{}\n\nfunc (f *Filter) Apply(in []float64, cb func(i int)) {}",
+			newSource: "type Filter struct{}\n\nfunc (f *Filter) Apply(samples []float64, cb func(index int)) {}",
+			wantBump:  bumpPatch,
+		},
+		{
+			name:      "interface method parameter renamed",
+			oldSource: "type Source interface{ Read(p []byte) (n int, err error) }",
+			newSource: "type Source interface{ Read(buf []byte) (int, error) }",
+			wantBump:  bumpPatch,
+		},
+		{
+			name:         "parameter type changed",
+			oldSource:    "func Scale(x float64) float64 { return x }",
+			newSource:    "func Scale(x float32) float32 { return x }",
+			wantBreaking: []string{"example.com/m.Scale"},
+			wantBump:     bumpMajor,
+		},
+		{
+			name:         "type parameter constraint changed",
+			oldSource:    "func Max[T int | float64](a, b T) T { return a }",
+			newSource:    "func Max[T int](a, b T) T { return a }",
+			wantBreaking: []string{"example.com/m.Max"},
+			wantBump:     bumpMajor,
+		},
+		{
+			name:         "method added to interface",
+			oldSource:    "type Source interface{ Read() int }",
+			newSource:    "type Source interface {\n\tRead() int\n\tClose() error\n}",
+			wantBreaking: []string{"example.com/m.Source.Close"},
+			wantBump:     bumpMajor,
+		},
+		{
+			name:         "function removed",
+			oldSource:    "func Old() {}\n\nfunc Kept() {}",
+			newSource:    "func Kept() {}",
+			wantBreaking: []string{"example.com/m.Old"},
+			wantBump:     bumpMajor,
+		},
+		{
+			name:      "function added",
+			oldSource: "func Kept() {}",
+			newSource: "func Kept() {}\n\nfunc New() {}",
+			wantAdded: []string{"example.com/m.New"},
+			wantBump:  bumpMinor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := compareAPI(loadTestAPI(t, tt.oldSource), loadTestAPI(t, tt.newSource))
+
+			var breaking, added []string
+			for _, change := range changes {
+				if change.breaking {
+					breaking = append(breaking, change.name)
+				} else {
+					added = append(added, change.name)
+				}
+			}
+			if !reflect.DeepEqual(breaking, tt.wantBreaking) {
+				t.Errorf("compareAPI() breaking = %q, want %q", breaking, tt.wantBreaking)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("compareAPI() added = %q, want %q", added, tt.wantAdded)
+			}
+			if got := apiBump(changes); got != tt.wantBump {
+				t.Errorf("apiBump() = %q, want %q", got, tt.wantBump)
+			}
+		})
+	}
+}
+
+func TestIsSufficientBump(t *testing.T) {
+	tests := []struct {
+		base     string
+		released string
+		bump     string
+		want     bool
+	}{
+		{"v1.2.3", "v1.2.4", bumpPatch, true},
+		{"v1.2.3", "v1.2.4", bumpMinor, false},
+		{"v1.2.3", "v1.3.0", bumpMinor, true},
+		{"v1.2.3", "v1.3.0", bumpMajor, false},
+		{"v1.2.3", "v2.0.0", bumpMajor, true},
+		{"v0.4.2", "v0.5.0", bumpMajor, true},
+		{"v0.4.2", "v0.4.3", bumpMajor, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base+"_"+tt.released+"_"+tt.bump, func(t *testing.T) {
+			if got := isSufficientBump(tt.base, tt.released, tt.bump); got != tt.want {
+				t.Errorf("isSufficientBump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// benchHistoryFolder is the default folder of the stored benchmark results (one file per commit).
+const benchHistoryFolder = ".benchmarks"
+
+// benchComparison is the comparison of a benchmark metric with the baseline.
+type benchComparison struct {
+	name     string
+	unit     string
+	baseline float64
+	current  float64
+	delta    float64
+	pValue   float64
+	status   string
+}
+
+// benchCommand runs the benchmarks of a module or workspace, stores the results by commit and
+// compares them with a baseline.
+func benchCommand(args []string) {
+	// Define a flag set for the "bench" command
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace or module (defaults to current working directory)")
+	pattern := fs.String("bench", ".", "Regular expression selecting the benchmarks (go test -bench)")
+	count := fs.Int("count", 10, "Number of runs of each benchmark (go test -count)")
+	historyPath := fs.String("history", "", "Folder of the stored results (default: <path>/"+benchHistoryFolder+")")
+	baseline := fs.String("baseline", "", "Commit or tag to compare with (default: the latest stored results of another commit)")
+	threshold := fs.Float64("threshold", 5, "Change in percent above which a significant slowdown is a regression")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	noSave := slices.Contains(positional, "nosave")
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if *historyPath == "" {
+		*historyPath = filepath.Join(*folderPath, benchHistoryFolder)
+	}
+
+	commit, err := benchCommitKey(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	// Run the benchmarks of every module
+	var output bytes.Buffer
+	failed := false
+	for _, module := range modules {
+		fmt.Printf("=== %s ===\n", module.Path)
+		//nolint:gosec // G204: Safe usage - the arguments are passed to go test
+		cmd := exec.Command("go", "test", "-run", "^$", "-bench", *pattern, "-benchmem", "-count", strconv.Itoa(*count), "./...")
+		cmd.Dir = filepath.Join(*folderPath, module.Folder)
+		cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Printf("Benchmarks of %s failed: %v\n", module.Path, err)
+			failed = true
+		}
+	}
+	fmt.Println()
+	if failed {
+		os.Exit(1)
+	}
+
+	current, err := parseBenchmarks(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(current) == 0 {
+		fmt.Println("No benchmarks found.")
+		return
+	}
+
+	// Store the results of the commit
+	if !noSave {
+		err = os.MkdirAll(*historyPath, 0o750)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(*historyPath, commit+".txt"), output.Bytes(), 0o600)
+		}
+		if err != nil {
+			fmt.Println("Error storing the results:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Results stored in %s\n", filepath.Join(*historyPath, commit+".txt"))
+	}
+
+	// Compare with the baseline
+	baselinePath, err := findBenchBaseline(*folderPath, *historyPath, *baseline, commit)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if baselinePath == "" {
+		fmt.Println("No baseline found, nothing to compare with.")
+		return
+	}
+	//nolint:gosec // G304: Safe usage - the file is in the history folder
+	content, err := os.ReadFile(baselinePath)
+	if err != nil {
+		fmt.Println("Error reading the baseline:", err)
+		os.Exit(1)
+	}
+	previous, err := parseBenchmarks(bytes.NewReader(content))
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Baseline: %s\n\n", strings.TrimSuffix(filepath.Base(baselinePath), ".txt"))
+	comparisons := compareBenchmarks(previous, current, *threshold)
+	regressions := printBenchComparisons(comparisons)
+	if regressions > 0 {
+		fmt.Printf("\n%d regression(s) above %.1f%%.\n", regressions, *threshold)
+		os.Exit(1)
+	}
+	fmt.Println("\nNo regressions.")
+}
+
+// benchCommitKey returns the key of the results of the current state: the abbreviated commit,
+// with suffix "-dirty" if there are uncommitted changes.
+func benchCommitKey(folderPath string) (string, error) {
+	commit, err := gitOutput(folderPath, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	status, err := gitOutput(folderPath, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return "", err
+	}
+	if status != "" {
+		commit += "-dirty"
+	}
+	return commit, nil
+}
+
+// findBenchBaseline returns the stored results to compare with: those of the commit or tag
+// baseline, or by default the most recently stored results of another commit. An empty string
+// is returned if there are none.
+func findBenchBaseline(folderPath, historyPath, baseline, commit string) (string, error) {
+	if baseline != "" {
+		baselineCommit, err := gitOutput(folderPath, "rev-parse", "--short", baseline+"^{commit}")
+		if err != nil {
+			return "", err
+		}
+		baselinePath := filepath.Join(historyPath, baselineCommit+".txt")
+		if !fileExists(baselinePath) {
+			return "", fmt.Errorf("no stored results for %s (%s), run the benchmarks at that commit first", baseline, baselineCommit)
+		}
+		return baselinePath, nil
+	}
+
+	entries, err := os.ReadDir(historyPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	latest := ""
+	var latestTime time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt") || entry.Name() == commit+".txt" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return "", err
+		}
+		if info.ModTime().After(latestTime) {
+			latest = filepath.Join(historyPath, entry.Name())
+			latestTime = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// compareBenchmarks compares the medians of the benchmarks present in both results. A change is
+// significant if the Mann-Whitney U test gives p < 0.05; a significant change above threshold
+// percent in the worse direction is a regression (see higherIsBetter).
+func compareBenchmarks(previous, current benchmarkSamples, threshold float64) []benchComparison {
+	var comparisons []benchComparison
+	for name, units := range current {
+		for unit, values := range units {
+			baselineValues := previous[name][unit]
+			if len(baselineValues) == 0 {
+				continue
+			}
+
+			comparison := benchComparison{
+				name:     name,
+				unit:     unit,
+				baseline: median(baselineValues),
+				current:  median(values),
+				pValue:   mannWhitneyPValue(baselineValues, values),
+				status:   "~",
+			}
+			if comparison.baseline != 0 {
+				comparison.delta = 100 * (comparison.current - comparison.baseline) / comparison.baseline
+			}
+			if comparison.pValue < significanceLevel {
+				change := comparison.delta
+				if higherIsBetter(unit) {
+					change = -change
+				}
+				switch {
+				case change > threshold:
+					comparison.status = "REGRESSION"
+				case change < -threshold:
+					comparison.status = "improved"
+				}
+			}
+			comparisons = append(comparisons, comparison)
+		}
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].name != comparisons[j].name {
+			return comparisons[i].name < comparisons[j].name
+		}
+		return comparisons[i].unit < comparisons[j].unit
+	})
+	return comparisons
+}
+
+// higherIsBetter reports whether larger values of the unit are better: throughput units like
+// MB/s (b.SetBytes) or custom rates ending in "/s". For all other units (ns/op, B/op,
+// allocs/op) lower is better.
+func higherIsBetter(unit string) bool {
+	return strings.HasSuffix(unit, "/s")
+}
+
+// printBenchComparisons prints the comparison table and returns the number of regressions.
+func printBenchComparisons(comparisons []benchComparison) int {
+	regressions := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "BENCHMARK\tUNIT\tBASELINE\tCURRENT\tDELTA\tP\tSTATUS")
+	for _, comparison := range comparisons {
+		delta := fmt.Sprintf("%+.1f%%", comparison.delta)
+		if math.Abs(comparison.delta) < 0.05 {
+			delta = "0.0%"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%.3f\t%s\n", comparison.name, comparison.unit,
+			formatBenchValue(comparison.baseline), formatBenchValue(comparison.current),
+			delta, comparison.pValue, comparison.status)
+		if comparison.status == "REGRESSION" {
+			regressions++
+		}
+	}
+	_ = writer.Flush()
+	return regressions
+}
+
+// formatBenchValue formats a measured value with 4 significant digits without exponent.
+func formatBenchValue(value float64) string {
+	if math.Abs(value) >= 10000 {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
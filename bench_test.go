@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestCompareBenchmarks(t *testing.T) {
+	previous := benchmarkSamples{
+		"BenchmarkFilter": {
+			"ns/op": {100, 101, 102, 103, 104, 105},
+			"MB/s":  {100, 101, 102, 103, 104, 105},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		current []float64
+		want    map[string]string
+	}{
+		{
+			name:    "faster",
+			current: []float64{80, 81, 82, 83, 84, 85},
+			want:    map[string]string{"ns/op": "improved", "MB/s": "REGRESSION"},
+		},
+		{
+			name:    "slower",
+			current: []float64{120, 121, 122, 123, 124, 125},
+			want:    map[string]string{"ns/op": "REGRESSION", "MB/s": "improved"},
+		},
+		{
+			name:    "unchanged",
+			current: []float64{100, 101, 102, 103, 104, 105},
+			want:    map[string]string{"ns/op": "~", "MB/s": "~"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := benchmarkSamples{
+				"BenchmarkFilter": {"ns/op": tt.current, "MB/s": tt.current},
+			}
+			comparisons := compareBenchmarks(previous, current, 5)
+			if len(comparisons) != len(tt.want) {
+				t.Fatalf("compareBenchmarks() returned %d comparisons, want %d", len(comparisons), len(tt.want))
+			}
+			for _, comparison := range comparisons {
+				if comparison.status != tt.want[comparison.unit] {
+					t.Errorf("compareBenchmarks() %s status = %q, want %q (delta %.1f%%, p %.3f)", comparison.unit,
+						comparison.status, tt.want[comparison.unit], comparison.delta, comparison.pValue)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// significanceLevel is the p-value below which a difference is considered significant (like benchstat).
+const significanceLevel = 0.05
+
+// benchmarkSuffix is the GOMAXPROCS suffix of benchmark names (e.g. "-8"), removed so results of
+// machines with different numbers of CPUs can be compared.
+var benchmarkSuffix = regexp.MustCompile(`-[0-9]+$`)
+
+// benchmarkSamples holds the measured values of the benchmarks by "<package>.<benchmark>" and unit
+// (ns/op, B/op, allocs/op, custom metrics).
+type benchmarkSamples map[string]map[string][]float64
+
+// parseBenchmarks reads the output of "go test -bench" (the format used by benchstat).
+func parseBenchmarks(r io.Reader) (benchmarkSamples, error) {
+	samples := make(benchmarkSamples)
+	packagePath := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "pkg: "); ok {
+			packagePath = strings.TrimSpace(value)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+
+		name := benchmarkSuffix.ReplaceAllString(fields[0], "")
+		if packagePath != "" {
+			name = packagePath + "." + name
+		}
+		if samples[name] == nil {
+			samples[name] = make(map[string][]float64)
+		}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			samples[name][fields[i+1]] = append(samples[name][fields[i+1]], value)
+		}
+	}
+	return samples, scanner.Err()
+}
+
+// median returns the median of values.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// mannWhitneyPValue returns the two-sided p-value of the Mann-Whitney U test of the samples x and y,
+// the test benchstat uses. The exact distribution is used for small samples without ties, the
+// normal approximation with tie correction otherwise.
+func mannWhitneyPValue(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the combined samples, ties get the average rank
+	type rankedValue struct {
+		value float64
+		fromX bool
+	}
+	values := make([]rankedValue, 0, n1+n2)
+	for _, value := range x {
+		values = append(values, rankedValue{value, true})
+	}
+	for _, value := range y {
+		values = append(values, rankedValue{value, false})
+	}
+	slices.SortFunc(values, func(a, b rankedValue) int {
+		switch {
+		case a.value < b.value:
+			return -1
+		case a.value > b.value:
+			return 1
+		}
+		return 0
+	})
+
+	rankSumX := 0.0
+	tieCorrection := 0.0
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].value == values[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if values[k].fromX {
+				rankSumX += rank
+			}
+		}
+		if ties := float64(j - i); ties > 1 {
+			tieCorrection += ties*ties*ties - ties
+		}
+		i = j
+	}
+
+	u := rankSumX - float64(n1*(n1+1))/2
+	uMin := math.Min(u, float64(n1*n2)-u)
+
+	if tieCorrection == 0 && n1 <= 50 && n2 <= 50 {
+		return math.Min(1, 2*exactUCumulative(n1, n2, int(uMin)))
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	return math.Min(1, math.Erfc(math.Max(z, 0)/math.Sqrt2))
+}
+
+// exactUCumulative returns P(U <= u) for samples of sizes n1 and n2 without ties.
+func exactUCumulative(n1, n2, u int) float64 {
+	// counts[i][j][k] is the number of arrangements of i and j values with U = k, computed
+	// layer by layer: f(i, j, k) = f(i-1, j, k-j) + f(i, j-1, k)
+	maxU := n1 * n2
+	previous := make([][]float64, n2+1)
+	for j := range previous {
+		previous[j] = make([]float64, maxU+1)
+		previous[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		current := make([][]float64, n2+1)
+		current[0] = make([]float64, maxU+1)
+		current[0][0] = 1
+		for j := 1; j <= n2; j++ {
+			current[j] = make([]float64, maxU+1)
+			for k := 0; k <= i*j; k++ {
+				current[j][k] = current[j-1][k]
+				if k >= j {
+					current[j][k] += previous[j][k-j]
+				}
+			}
+		}
+		previous = current
+	}
+
+	total, below := 0.0, 0.0
+	for k, count := range previous[n2] {
+		total += count
+		if k <= u {
+			below += count
+		}
+	}
+	return below / total
+}
@@ -13,8 +13,18 @@ cd /d "%~dp0"
 echo.
 echo [1] Go Modules ueberpruefen...
 echo ------------------------------
-go mod tidy
-go mod verify
+REM Mit vendor-Ordner (go mod vendor im Modul oder go work vendor im Workspace) wird offline aus
+REM vendor gebaut, go mod tidy wuerde dagegen Module nachladen
+set VENDOR_DIR=vendor
+set GOWORK_FILE=
+for /f "tokens=* usebackq" %%W in (`go env GOWORK`) do set GOWORK_FILE=%%W
+if defined GOWORK_FILE if not "!GOWORK_FILE!"=="off" for %%W in ("!GOWORK_FILE!") do set VENDOR_DIR=%%~dpWvendor
+if exist "!VENDOR_DIR!\modules.txt" (
+    echo [i] vendor-Ordner gefunden ^(!VENDOR_DIR!^): go mod tidy uebersprungen, Pruefung mit 'vasgotools vendor verify'
+) else (
+    go mod tidy
+    go mod verify
+)
 
 echo.
 echo [2] Build-Ueberpruefung...
@@ -24,8 +34,15 @@ FOR /F "tokens=* USEBACKQ" %%F IN (`git describe --tags`) DO (
 SET GIT_VERSION_INFO=%%F
 )
 ECHO %GIT_VERSION_INFO%
+for /f "tokens=2" %%i in ('findstr /b "module " go.mod') do set MODULE_NAME=%%i
+FOR /F "tokens=* USEBACKQ" %%D IN (`powershell -NoProfile -Command "(Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')"`) DO (
+SET BUILD_DATE=%%D
+)
 
-go build -ldflags "-X main.version=%GIT_VERSION_INFO%" ./...
+REM Version des Pakets internal/version setzen (und main.version fuer Anwendungen ohne dieses Paket)
+set LDFLAGS=-X main.version=%GIT_VERSION_INFO% -X %MODULE_NAME%/internal/version.Version=%GIT_VERSION_INFO% -X %MODULE_NAME%/internal/version.BuildDate=%BUILD_DATE%
+
+go build -ldflags "%LDFLAGS%" ./...
 if errorlevel 1 (
     echo [X] Build fehlgeschlagen
     exit /b 1
@@ -33,10 +50,15 @@ if errorlevel 1 (
     echo [OK] Build erfolgreich
 )
 
+REM Go-Dateien des Moduls ohne vendor, ein Pfad pro Zeile (leer ohne Go-Dateien); die Tools
+REM werden je Datei mit dem Pfad in Anfuehrungszeichen aufgerufen
+dir /s /b /a-d *.go 2>nul | findstr /v /i "\\vendor\\" > go_files_temp.txt
+
 echo.
 echo [3] Code-Formatierung ^(gofmt^)...
 echo ----------------------------------
-gofmt -d . > gofmt_temp.txt 2>&1
+type nul > gofmt_temp.txt
+for /f "usebackq delims=" %%F in ("go_files_temp.txt") do gofmt -d "%%F" >> gofmt_temp.txt 2>&1
 if exist gofmt_temp.txt (
     for %%A in (gofmt_temp.txt) do set size=%%~zA
     if !size! gtr 0 (
@@ -55,7 +77,8 @@ where goimports >nul 2>&1
 if errorlevel 1 (
     echo [i] goimports nicht installiert ^(go install golang.org/x/tools/cmd/goimports@latest^)
 ) else (
-    goimports -l . > goimports_temp.txt 2>&1
+    type nul > goimports_temp.txt
+    for /f "usebackq delims=" %%F in ("go_files_temp.txt") do goimports -l "%%F" >> goimports_temp.txt 2>&1
     if exist goimports_temp.txt (
         for %%A in (goimports_temp.txt) do set size=%%~zA
         if !size! gtr 0 (
@@ -143,12 +166,15 @@ echo.
 echo [10] Code-Statistiken...
 echo -------------------------
 set GO_FILES=0
-for /f %%a in ('dir /s /b *.go 2^>nul ^| find /c /v ""') do set GO_FILES=%%a
+for /f %%a in ('find /c /v "" ^< go_files_temp.txt') do set GO_FILES=%%a
 echo [*] Go-Dateien: !GO_FILES!
 
 set GO_LINES=0
-for /f %%a in ('type *.go 2^>nul ^| find /c /v ""') do set GO_LINES=%%a
+for /f "usebackq delims=" %%F in ("go_files_temp.txt") do (
+    for /f %%a in ('type "%%F" ^| find /c /v ""') do set /a GO_LINES+=%%a
+)
 echo [*] Zeilen Code: !GO_LINES!
+del go_files_temp.txt
 
 for /f %%a in ('go list ./... 2^>nul ^| find /c /v ""') do set PACKAGES=%%a
 echo [*] Packages: !PACKAGES!
@@ -160,5 +186,5 @@ echo.
 echo [*] Tipps:
 echo   - Behebe kritische Issues aus golangci-lint
 echo   - Ueberpruefe Vulnerabilities mit govulncheck
-echo   - Achte auf mindestens 80%% Test-Coverage
+echo   - Achte auf ausreichende Test-Coverage (Mindestwerte pruefen: vasgotools coverage)
 echo   - Verwende 'go fmt' fuer einheitliche Formatierung
@@ -12,16 +12,38 @@ cd "$(dirname "$0")" || exit 1
 echo ""
 echo "📋 1. Go Modules überprüfen..."
 echo "------------------------------"
-go mod tidy
-go mod verify
+# Mit vendor-Ordner (go mod vendor im Modul oder go work vendor im Workspace) wird offline aus
+# vendor/ gebaut, go mod tidy würde dagegen Module nachladen
+VENDOR_DIR="vendor"
+GOWORK_FILE=$(go env GOWORK)
+if [ -n "$GOWORK_FILE" ] && [ "$GOWORK_FILE" != "off" ]; then
+    VENDOR_DIR="$(dirname "$GOWORK_FILE")/vendor"
+fi
+if [ -f "$VENDOR_DIR/modules.txt" ]; then
+    echo "ℹ️  vendor-Ordner gefunden ($VENDOR_DIR): go mod tidy übersprungen, Prüfung mit 'vasgotools vendor verify'"
+else
+    go mod tidy
+    go mod verify
+fi
+
+# Go-Dateien des Moduls (ohne vendor/) mit der find-Aktion aus den Argumenten, z.B. -exec gofmt -l {} +
+# (ohne Go-Dateien wird das Tool nicht aufgerufen, Pfade mit Leerzeichen bleiben ein Argument)
+go_source_files() {
+    find . -path ./vendor -prune -o -type f -name "*.go" "$@"
+}
 
 echo ""
 echo "🔧 2. Build-Überprüfung..."
 echo "-------------------------"
 
 VERSION=$(git describe --tags)
+MODULE_NAME=$(grep "^module " go.mod | awk '{print $2}')
+BUILD_DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+
+# Version des Pakets internal/version setzen (und main.version für Anwendungen ohne dieses Paket)
+LDFLAGS="-X main.version=$VERSION -X $MODULE_NAME/internal/version.Version=$VERSION -X $MODULE_NAME/internal/version.BuildDate=$BUILD_DATE"
 
-if go build -ldflags "-X main.version=$VERSION" ./...; then
+if go build -ldflags "$LDFLAGS" ./...; then
     echo "✅ Build erfolgreich"
 else
     echo "❌ Build fehlgeschlagen"
@@ -31,7 +53,7 @@ fi
 echo ""
 echo "🎨 3. Code-Formatierung (gofmt)..."
 echo "----------------------------------"
-GOFMT_OUTPUT=$(gofmt -d .)
+GOFMT_OUTPUT=$(go_source_files -exec gofmt -d {} +)
 if [ -n "$GOFMT_OUTPUT" ]; then
     echo "⚠️  Code-Formatierung Probleme gefunden:"
     echo "$GOFMT_OUTPUT"
@@ -43,7 +65,7 @@ echo ""
 echo "📦 4. Imports (goimports)..."
 echo "---------------------------"
 if command -v goimports >/dev/null 2>&1; then
-    GOIMPORTS_OUTPUT=$(goimports -d .)
+    GOIMPORTS_OUTPUT=$(go_source_files -exec goimports -d {} +)
     if [ -n "$GOIMPORTS_OUTPUT" ]; then
         echo "⚠️  Import Probleme gefunden:"
         echo "$GOIMPORTS_OUTPUT"
@@ -118,8 +140,8 @@ fi
 echo ""
 echo "📋 10. Code-Statistiken..."
 echo "-------------------------"
-echo "📁 Go-Dateien: $(find . -name "*.go" | wc -l)"
-echo "📏 Zeilen Code: $(find . -name "*.go" -exec cat {} \; | wc -l)"
+echo "📁 Go-Dateien: $(go_source_files -print | wc -l)"
+echo "📏 Zeilen Code: $(go_source_files -exec cat {} + | wc -l)"
 echo "📦 Packages: $(go list ./... | wc -l)"
 
 echo ""
@@ -129,6 +151,6 @@ echo ""
 echo "💡 Tipps:"
 echo "  - Behebe kritische Issues aus golangci-lint"
 echo "  - Überprüfe Vulnerabilities mit govulncheck"
-echo "  - Achte auf mindestens 80% Test-Coverage"
+echo "  - Achte auf ausreichende Test-Coverage (Mindestwerte prüfen: vasgotools coverage)"
 echo "  - Verwende 'go fmt' für einheitliche Formatierung"
 echo "  - Version: $VERSION"
\ No newline at end of file
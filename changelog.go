@@ -0,0 +1,382 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	changelogFileName = "CHANGELOG.md"
+	changelogBOM      = "\uFEFF"
+	unreleasedHeading = "## [Unreleased]"
+)
+
+// changelogSections are the change types of Keep a Changelog in their usual order.
+var changelogSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// releaseHeading matches "## [1.2.3] - 2026-01-31".
+var releaseHeading = regexp.MustCompile(`^## \[([^\]]+)\] - (\d{4}-\d{2}-\d{2})$`)
+
+// changelog is a changelog file split into lines. A byte order mark and CRLF line endings are kept.
+type changelog struct {
+	path  string
+	lines []string
+	bom   bool
+	crlf  bool
+}
+
+func printChangelogUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  vasgotools.exe changelog add --type added|changed|deprecated|removed|fixed|security \"text\" [--path <module>]")
+	fmt.Println("  vasgotools.exe changelog release <version> [--path <module>] [nogit]")
+	fmt.Println("  vasgotools.exe changelog check [--path <module>]")
+}
+
+// changelogCommand manages the CHANGELOG.md (or CHANGELOG.txt) file of a module.
+func changelogCommand(args []string) {
+	if len(args) < 1 {
+		printChangelogUsage()
+		os.Exit(1)
+	}
+	action := args[0]
+
+	// Define a flag set for the "changelog" command
+	fs := flag.NewFlagSet("changelog "+action, flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module (defaults to current working directory)")
+	changeType := fs.String("type", "", "Type of the change: added, changed, deprecated, removed, fixed or security")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	noGit := slices.Contains(positional, "nogit")
+	positional = slices.DeleteFunc(positional, func(arg string) bool { return arg == "nogit" })
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	log, err := readChangelog(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	switch action {
+	case "add":
+		text := strings.TrimSpace(strings.Join(positional, " "))
+		if text == "" {
+			fmt.Println("Error: the text of the entry is missing.")
+			printChangelogUsage()
+			os.Exit(1)
+		}
+		section := changelogSection(*changeType)
+		if section == "" {
+			fmt.Printf("Error: unknown type '%s' (use added, changed, deprecated, removed, fixed or security)\n", *changeType)
+			os.Exit(1)
+		}
+		log.addEntry(section, text)
+		err = log.write()
+		if err != nil {
+			fmt.Println("Error writing changelog:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Entry added to %s of %s.\n", section, filepath.Base(log.path))
+	case "release":
+		if len(positional) != 1 {
+			printChangelogUsage()
+			os.Exit(1)
+		}
+		releaseChangelog(log, *folderPath, positional[0], noGit)
+	case "check":
+		problems := log.validate()
+		for _, problem := range problems {
+			fmt.Printf("%s:%s\n", filepath.Base(log.path), problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%s is valid.\n", filepath.Base(log.path))
+	default:
+		fmt.Printf("Unknown changelog action: %s\n", action)
+		printChangelogUsage()
+		os.Exit(1)
+	}
+}
+
+// releaseChangelog moves the unreleased changes to a new release, then commits the changelog and
+// creates the release tag (unless noGit is set).
+func releaseChangelog(log *changelog, folderPath, version string, noGit bool) {
+	version = strings.TrimPrefix(version, "v")
+	if _, ok := parseSemver("v" + version); !ok {
+		fmt.Printf("Error: invalid version '%s' (use X.Y.Z)\n", version)
+		os.Exit(1)
+	}
+
+	problems := log.validate()
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("%s:%s\n", filepath.Base(log.path), problem)
+		}
+		fmt.Println("Error: fix the changelog before the release.")
+		os.Exit(1)
+	}
+	err := log.release(version, time.Now().Format(time.DateOnly))
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	err = log.write()
+	if err != nil {
+		fmt.Println("Error writing changelog:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s: release %s created.\n", filepath.Base(log.path), version)
+
+	if noGit {
+		fmt.Println("Commit and tag skipped.")
+		return
+	}
+	prefix, err := moduleTagPrefix(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	tag := prefix + "v" + version
+	fileName := filepath.Base(log.path)
+	_, err = gitOutput(folderPath, "add", fileName)
+	if err == nil {
+		_, err = gitOutput(folderPath, "commit", "-m", "Release "+tag, "--", fileName)
+	}
+	if err == nil {
+		_, err = gitOutput(folderPath, "tag", "-a", tag, "-m", "Release "+tag)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Changelog committed and tagged %s (publish with 'git push origin %s').\n", tag, tag)
+}
+
+// changelogSection returns the section heading for a change type ("" if unknown).
+func changelogSection(changeType string) string {
+	for _, section := range changelogSections {
+		if strings.EqualFold(section, changeType) {
+			return section
+		}
+	}
+	return ""
+}
+
+// createChangelogFile creates the CHANGELOG.md skeleton.
+func createChangelogFile(folderPath string) error {
+	err := os.WriteFile(filepath.Join(folderPath, changelogFileName), []byte(changelogTemplate), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", changelogFileName, err)
+	}
+	return nil
+}
+
+// readChangelog reads CHANGELOG.md, or CHANGELOG.txt if there is no CHANGELOG.md, from folderPath.
+func readChangelog(folderPath string) (*changelog, error) {
+	filePath := filepath.Join(folderPath, changelogFileName)
+	if !fileExists(filePath) && fileExists(filepath.Join(folderPath, "CHANGELOG.txt")) {
+		filePath = filepath.Join(folderPath, "CHANGELOG.txt")
+	}
+
+	//nolint:gosec // G304: Safe usage - the changelog of the module
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	text := string(content)
+	log := &changelog{path: filePath}
+	text, log.bom = strings.CutPrefix(text, changelogBOM)
+	log.crlf = strings.Contains(text, "\r\n")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	log.lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	return log, nil
+}
+
+// write writes the changelog back to its file.
+func (log *changelog) write() error {
+	text := strings.Join(log.lines, "\n") + "\n"
+	if log.crlf {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	if log.bom {
+		text = changelogBOM + text
+	}
+	return os.WriteFile(log.path, []byte(text), 0o600)
+}
+
+// releaseIndex returns the index of the first release heading ("## ") at or after start, or the number
+// of lines if there is none.
+func (log *changelog) releaseIndex(start int) int {
+	for i := start; i < len(log.lines); i++ {
+		if strings.HasPrefix(log.lines[i], "## ") {
+			return i
+		}
+	}
+	return len(log.lines)
+}
+
+// unreleased returns the index of the [Unreleased] heading and the index of the heading following it.
+// The heading is created after the file header if it is missing.
+func (log *changelog) unreleased() (start, end int) {
+	start = slices.Index(log.lines, unreleasedHeading)
+	if start < 0 {
+		start = log.releaseIndex(0)
+		log.insert(start, unreleasedHeading, "")
+	}
+	return start, log.releaseIndex(start + 1)
+}
+
+// insert inserts lines before index.
+func (log *changelog) insert(index int, lines ...string) {
+	log.lines = slices.Insert(log.lines, index, lines...)
+}
+
+// addEntry adds an entry to the section of the unreleased changes. A missing section is created
+// in the order of Keep a Changelog.
+func (log *changelog) addEntry(section, text string) {
+	start, end := log.unreleased()
+	entry := "- " + text
+
+	sectionIndex := slices.Index(log.lines[start:end], "### "+section)
+	if sectionIndex >= 0 {
+		// Append after the last entry of the section
+		index := start + sectionIndex + 1
+		last := index
+		for ; index < end && !strings.HasPrefix(log.lines[index], "#"); index++ {
+			if strings.TrimSpace(log.lines[index]) != "" {
+				last = index + 1
+			}
+		}
+		log.insert(last, entry)
+		return
+	}
+
+	// Insert the section before the first section that follows it in the usual order
+	index := end
+	order := slices.Index(changelogSections, section)
+	for i := start + 1; i < end; i++ {
+		if name, ok := strings.CutPrefix(log.lines[i], "### "); ok && slices.Index(changelogSections, name) > order {
+			index = i
+			break
+		}
+	}
+	if index == end {
+		for index > start+1 && strings.TrimSpace(log.lines[index-1]) == "" {
+			index--
+		}
+	}
+
+	// Separate the section from the previous section and from the following heading by blank lines
+	lines := []string{"### " + section, entry}
+	if index > start+1 && strings.TrimSpace(log.lines[index-1]) != "" {
+		lines = slices.Insert(lines, 0, "")
+	}
+	if index < len(log.lines) && strings.TrimSpace(log.lines[index]) != "" {
+		lines = append(lines, "")
+	}
+	log.insert(index, lines...)
+}
+
+// release moves the unreleased changes to a release with the given version and date.
+func (log *changelog) release(version, date string) error {
+	start, end := log.unreleased()
+	if !slices.ContainsFunc(log.lines[start+1:end], func(line string) bool { return strings.HasPrefix(line, "- ") }) {
+		return fmt.Errorf("there are no unreleased changes")
+	}
+	for _, line := range log.lines {
+		if match := releaseHeading.FindStringSubmatch(line); match != nil && compareSemver("v"+version, "v"+match[1]) <= 0 {
+			return fmt.Errorf("version %s is not higher than the released version %s", version, match[1])
+		}
+	}
+
+	log.insert(start+1, "", fmt.Sprintf("## [%s] - %s", version, date))
+	return nil
+}
+
+// validate checks the structure of the changelog and returns the problems found, prefixed with the line number.
+func (log *changelog) validate() []string {
+	var problems []string
+	report := func(index int, format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("%d: %s", index+1, fmt.Sprintf(format, args...)))
+	}
+
+	releases := 0
+	previousVersion, previousDate := "", ""
+	var sections []string
+	for i, line := range log.lines {
+		switch {
+		case line == unreleasedHeading:
+			if releases > 0 {
+				report(i, "[Unreleased] must be the first release")
+			}
+			releases++
+			sections = nil
+		case strings.HasPrefix(line, "## "):
+			match := releaseHeading.FindStringSubmatch(line)
+			if match == nil {
+				report(i, "invalid release heading '%s' (use '## [X.Y.Z] - YYYY-MM-DD')", line)
+				continue
+			}
+			if releases == 0 {
+				report(i, "missing [Unreleased] before the first release")
+			}
+			releases++
+			sections = nil
+
+			version, date := match[1], match[2]
+			if _, ok := parseSemver("v" + version); !ok {
+				report(i, "invalid version '%s'", version)
+			} else {
+				if previousVersion != "" && compareSemver("v"+version, "v"+previousVersion) >= 0 {
+					report(i, "version %s must be lower than the version %s above", version, previousVersion)
+				}
+				previousVersion = version
+			}
+			if _, err := time.Parse(time.DateOnly, date); err != nil {
+				report(i, "invalid date '%s'", date)
+			} else {
+				if previousDate != "" && date > previousDate {
+					report(i, "date %s must not be after the date %s above", date, previousDate)
+				}
+				previousDate = date
+			}
+		case strings.HasPrefix(line, "### "):
+			section := strings.TrimPrefix(line, "### ")
+			switch {
+			case releases == 0:
+				report(i, "section '%s' outside of a release", section)
+			case !slices.Contains(changelogSections, section):
+				report(i, "unknown section '%s' (use %s)", section, strings.Join(changelogSections, ", "))
+			case slices.Contains(sections, section):
+				report(i, "duplicate section '%s'", section)
+			}
+			sections = append(sections, section)
+		case strings.HasPrefix(line, "- "):
+			if len(sections) == 0 {
+				report(i, "entry outside of a section")
+			}
+		}
+	}
+	if releases == 0 {
+		problems = append(problems, "missing [Unreleased] heading")
+	}
+	return problems
+}
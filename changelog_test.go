@@ -0,0 +1,219 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestChangelog returns a changelog of text, lines are separated by "\n".
+func newTestChangelog(text string) *changelog {
+	return &changelog{lines: strings.Split(strings.TrimSuffix(text, "\n"), "\n")}
+}
+
+const testChangelogHeader = "# Changelog\n\nAll notable changes.\n\n"
+
+func TestChangelogAddEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		section string
+		entry   string
+		want    string
+	}{
+		{
+			name:    "append to existing section",
+			text:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n## [1.0.0] - 2026-01-01\n### Added\n- old\n",
+			section: "Added",
+			entry:   "b",
+			want:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n- b\n\n## [1.0.0] - 2026-01-01\n### Added\n- old\n",
+		},
+		{
+			name:    "create section in Keep a Changelog order",
+			text:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n### Fixed\n- f\n",
+			section: "Changed",
+			entry:   "c",
+			want:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n### Changed\n- c\n\n### Fixed\n- f\n",
+		},
+		{
+			name:    "create last section before next release",
+			text:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n## [1.0.0] - 2026-01-01\n",
+			section: "Security",
+			entry:   "s",
+			want:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n### Security\n- s\n\n## [1.0.0] - 2026-01-01\n",
+		},
+		{
+			name:    "create first section in empty unreleased",
+			text:    testChangelogHeader + "## [Unreleased]\n\n## [1.0.0] - 2026-01-01\n",
+			section: "Fixed",
+			entry:   "f",
+			want:    testChangelogHeader + "## [Unreleased]\n### Fixed\n- f\n\n## [1.0.0] - 2026-01-01\n",
+		},
+		{
+			name:    "create missing unreleased heading",
+			text:    testChangelogHeader + "## [1.0.0] - 2026-01-01\n### Added\n- old\n",
+			section: "Added",
+			entry:   "new",
+			want:    testChangelogHeader + "## [Unreleased]\n### Added\n- new\n\n## [1.0.0] - 2026-01-01\n### Added\n- old\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newTestChangelog(tt.text)
+			log.addEntry(tt.section, tt.entry)
+			if got := strings.Join(log.lines, "\n") + "\n"; got != tt.want {
+				t.Errorf("addEntry() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelogRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "first release",
+			text:    testChangelogHeader + "## [Unreleased]\n### Added\n- a\n",
+			version: "0.1.0",
+			want:    testChangelogHeader + "## [Unreleased]\n\n## [0.1.0] - 2026-10-18\n### Added\n- a\n",
+		},
+		{
+			name:    "release after previous release",
+			text:    testChangelogHeader + "## [Unreleased]\n### Fixed\n- f\n\n## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+			version: "1.0.1",
+			want:    testChangelogHeader + "## [Unreleased]\n\n## [1.0.1] - 2026-10-18\n### Fixed\n- f\n\n## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+		},
+		{
+			name:    "no unreleased changes",
+			text:    testChangelogHeader + "## [Unreleased]\n\n## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+			version: "1.1.0",
+			wantErr: true,
+		},
+		{
+			name:    "version not higher than released version",
+			text:    testChangelogHeader + "## [Unreleased]\n### Fixed\n- f\n\n## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+			version: "1.0.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newTestChangelog(tt.text)
+			err := log.release(tt.version, "2026-10-18")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("release() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := strings.Join(log.lines, "\n") + "\n"; got != tt.want {
+				t.Errorf("release() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if problems := log.validate(); len(problems) > 0 {
+				t.Errorf("validate() after release = %q, want no problems", problems)
+			}
+		})
+	}
+}
+
+func TestChangelogValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "valid",
+			text: testChangelogHeader + "## [Unreleased]\n### Added\n- a\n\n## [1.1.0] - 2026-02-01\n### Fixed\n- f\n\n## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+		},
+		{
+			name: "missing unreleased",
+			text: testChangelogHeader + "## [1.0.0] - 2026-01-01\n### Added\n- a\n",
+			want: []string{"5: missing [Unreleased] before the first release"},
+		},
+		{
+			name: "no releases",
+			text: testChangelogHeader,
+			want: []string{"missing [Unreleased] heading"},
+		},
+		{
+			name: "invalid release heading",
+			text: testChangelogHeader + "## [Unreleased]\n\n## 1.0.0 (2026-01-01)\n",
+			want: []string{"7: invalid release heading '## 1.0.0 (2026-01-01)' (use '## [X.Y.Z] - YYYY-MM-DD')"},
+		},
+		{
+			name: "versions not descending",
+			text: testChangelogHeader + "## [Unreleased]\n\n## [1.0.0] - 2026-02-01\n\n## [1.1.0] - 2026-01-01\n",
+			want: []string{"9: version 1.1.0 must be lower than the version 1.0.0 above"},
+		},
+		{
+			name: "dates not descending",
+			text: testChangelogHeader + "## [Unreleased]\n\n## [1.1.0] - 2026-01-01\n\n## [1.0.0] - 2026-02-01\n",
+			want: []string{"9: date 2026-02-01 must not be after the date 2026-01-01 above"},
+		},
+		{
+			name: "invalid date",
+			text: testChangelogHeader + "## [Unreleased]\n\n## [1.0.0] - 2026-13-01\n",
+			want: []string{"7: invalid date '2026-13-01'"},
+		},
+		{
+			name: "unknown and duplicate section",
+			text: testChangelogHeader + "## [Unreleased]\n### Improved\n- a\n### Added\n- b\n### Added\n- c\n",
+			want: []string{
+				"6: unknown section 'Improved' (use Added, Changed, Deprecated, Removed, Fixed, Security)",
+				"10: duplicate section 'Added'",
+			},
+		},
+		{
+			name: "entry outside of a section",
+			text: testChangelogHeader + "## [Unreleased]\n- a\n",
+			want: []string{"6: entry outside of a section"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestChangelog(tt.text).validate(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelogReadWrite(t *testing.T) {
+	// The byte order mark and CRLF line endings of an existing changelog are kept
+	content := changelogBOM + "# Changelog\r\n\r\n## [Unreleased]\r\n### Added\r\n- a\r\n"
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.txt"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := readChangelog(dir)
+	if err != nil {
+		t.Fatalf("readChangelog() error = %v", err)
+	}
+	if !log.bom || !log.crlf {
+		t.Errorf("readChangelog() bom = %v, crlf = %v, want true, true", log.bom, log.crlf)
+	}
+	log.addEntry("Added", "b")
+	if err := log.write(); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := changelogBOM + "# Changelog\r\n\r\n## [Unreleased]\r\n### Added\r\n- a\r\n- b\r\n"; string(got) != want {
+		t.Errorf("write() = %q, want %q", got, want)
+	}
+}
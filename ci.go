@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ciGitHub  = "github"
+	ciGitLab  = "gitlab"
+	ciJenkins = "jenkins"
+	ciNone    = "none"
+)
+
+// validateCI checks the value of the --ci option.
+func validateCI(ci string) error {
+	switch ci {
+	case ciGitHub, ciGitLab, ciJenkins, ciNone:
+		return nil
+	default:
+		return fmt.Errorf("unknown CI system '%s' (use github, gitlab, jenkins or none)", ci)
+	}
+}
+
+// createCIFiles creates the pipeline definition of the given CI system for the module in folderPath.
+func createCIFiles(ci, folderPath string) error {
+	if ci == ciNone {
+		return nil
+	}
+
+	mod, err := readGoMod(folderPath)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{
+		"MODULE_NAME": mod.Module.Path,
+		"GO_VERSION":  goMinorVersion(mod.Go),
+	}
+
+	switch ci {
+	case ciGitHub:
+		return writeCIFile(filepath.Join(folderPath, ".github", "workflows", "ci.yml"), renderTemplate(ciGitHubTemplate, values))
+	case ciGitLab:
+		return writeCIFile(filepath.Join(folderPath, ".gitlab-ci.yml"), renderTemplate(ciGitLabTemplate, values))
+	default:
+		return writeCIFile(filepath.Join(folderPath, "Jenkinsfile"), renderTemplate(jenkinsfileTemplate, values))
+	}
+}
+
+// createWorkspaceCIFiles creates the pipeline definition of the given CI system for the workspace in folderPath,
+// fanning out over the given module folders.
+func createWorkspaceCIFiles(ci, folderPath string, moduleFolders []string) error {
+	if ci == ciNone {
+		return nil
+	}
+
+	goVersion := ""
+	if isWorkspace(folderPath) {
+		work, err := readGoWork(folderPath)
+		if err != nil {
+			return err
+		}
+		goVersion = work.Go
+	}
+	values := map[string]string{
+		"MODULES":    quotedModuleList(moduleFolders),
+		"GO_VERSION": goMinorVersion(goVersion),
+	}
+
+	switch ci {
+	case ciGitHub:
+		return writeCIFile(filepath.Join(folderPath, ".github", "workflows", "ci.yml"), renderTemplate(ciGitHubWorkTemplate, values))
+	case ciGitLab:
+		return writeCIFile(filepath.Join(folderPath, ".gitlab-ci.yml"), renderTemplate(ciGitLabWorkTemplate, values))
+	default:
+		return writeCIFile(filepath.Join(folderPath, "Jenkinsfile"), renderTemplate(jenkinsfileWorkTemplate, values))
+	}
+}
+
+// goMinorVersion shortens a Go version to major.minor (e.g. "1.24.2" to "1.24"), the way Go container
+// images are tagged. An empty version results in "1", the latest Go 1.x release.
+func goMinorVersion(goVersion string) string {
+	if goVersion == "" {
+		return "1"
+	}
+	if parts := strings.Split(goVersion, "."); len(parts) > 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return goVersion
+}
+
+// quotedModuleList returns the module folders as a comma separated list of quoted strings,
+// which is valid in YAML flow sequences as well as in Groovy lists.
+func quotedModuleList(moduleFolders []string) string {
+	quoted := make([]string, 0, len(moduleFolders))
+	for _, moduleFolder := range moduleFolders {
+		quoted = append(quoted, "'"+filepath.ToSlash(moduleFolder)+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func writeCIFile(filePath, content string) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating folder for %s: %w", filepath.Base(filePath), err)
+	}
+	err = os.WriteFile(filePath, []byte(content), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Base(filePath), err)
+	}
+	return nil
+}
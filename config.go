@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// configFileName is the name of the optional vasgotools configuration file.
+// It is searched in the given folder and all of its parent folders, so a configuration
+// placed in the workspace root also applies to all modules of the workspace.
+const configFileName = "vasgotools.json"
+
+// config holds the settings read from the vasgotools.json configuration file.
+type config struct {
+	// Editor selects the editor integration: vscode, goland, vim, none or a custom command.
+	Editor   string         `json:"editor"`
+	Licenses licensesConfig `json:"licenses"`
+	Coverage coverageConfig `json:"coverage"`
+}
+
+// licensesConfig holds the license policy used by the "licenses" command.
+type licensesConfig struct {
+	// Disallowed lists the license identifiers that must not be shipped.
+	Disallowed []string `json:"disallowed"`
+	// AllowUnknown accepts modules whose license could not be detected.
+	AllowUnknown bool `json:"allowUnknown"`
+	// Exceptions lists module paths that are accepted regardless of their license.
+	Exceptions []string `json:"exceptions"`
+}
+
+// coverageConfig holds the minimum coverage checked by the "coverage" command.
+type coverageConfig struct {
+	// Minimum is the coverage in percent every module must reach (0: no check).
+	Minimum float64 `json:"minimum"`
+	// Modules overrides the minimum per module path.
+	Modules map[string]float64 `json:"modules"`
+}
+
+// defaultConfig returns the configuration used when no vasgotools.json is found.
+func defaultConfig() *config {
+	return &config{
+		Licenses: licensesConfig{
+			Disallowed: []string{"AGPL-3.0", "GPL-2.0", "GPL-3.0", "SSPL-1.0"},
+		},
+	}
+}
+
+// findConfigFile searches folderPath and its parent folders for the configuration file.
+// An empty string is returned if no configuration file exists.
+func findConfigFile(folderPath string) string {
+	dir, err := filepath.Abs(folderPath)
+	if err != nil {
+		return ""
+	}
+	for {
+		configPath := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadConfig loads the configuration for folderPath. Settings missing in the file keep their defaults.
+func loadConfig(folderPath string) (*config, error) {
+	cfg := defaultConfig()
+
+	configPath := findConfigFile(folderPath)
+	if configPath == "" {
+		return cfg, nil
+	}
+
+	//nolint:gosec // G304: Safe usage - configPath is controlled by the application
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", configPath, err)
+	}
+
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", configPath, err)
+	}
+	return cfg, nil
+}
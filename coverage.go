@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// coverageBlock is a block of a coverage profile: its number of statements and execution count.
+type coverageBlock struct {
+	statements int
+	count      int
+}
+
+// coverageProfile is a merged coverage profile keyed by "file:start,end" of the blocks.
+type coverageProfile struct {
+	mode   string
+	blocks map[string]*coverageBlock
+}
+
+// coverageStats are the statements and covered statements of a package or module.
+type coverageStats struct {
+	statements int
+	covered    int
+}
+
+func (stats coverageStats) percent() float64 {
+	if stats.statements == 0 {
+		return 0
+	}
+	return 100 * float64(stats.covered) / float64(stats.statements)
+}
+
+// String returns the coverage in percent, or "-" without statements.
+func (stats coverageStats) String() string {
+	if stats.statements == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", stats.percent())
+}
+
+// coverageCommand runs the tests of all workspace modules with coverage of all workspace packages,
+// merges the profiles and checks the coverage of each module against its minimum.
+func coverageCommand(args []string) {
+	// Define a flag set for the "coverage" command
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace or module (defaults to current working directory)")
+	outPath := fs.String("out", "", "Folder for the merged profile and the HTML report (default: <path>/coverage)")
+	minimum := fs.Float64("min", -1, "Minimum coverage in percent of every module (default: from vasgotools.json, 0 = no check)")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if *outPath == "" {
+		*outPath = filepath.Join(*folderPath, "coverage")
+	}
+	*outPath, err = filepath.Abs(*outPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if *minimum >= 0 {
+		cfg.Coverage.Minimum = *minimum
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(modules) == 0 {
+		fmt.Println("Error: no modules found in", *folderPath)
+		os.Exit(1)
+	}
+
+	err = os.MkdirAll(*outPath, 0o750)
+	if err != nil {
+		fmt.Println("Error creating the output folder:", err)
+		os.Exit(1)
+	}
+
+	// Run the tests of every module with coverage of the packages of all modules
+	coverPackages := make([]string, 0, len(modules))
+	for _, module := range modules {
+		coverPackages = append(coverPackages, module.Path+"/...")
+	}
+	merged := &coverageProfile{blocks: make(map[string]*coverageBlock)}
+	testsFailed := false
+	for _, module := range modules {
+		profilePath := filepath.Join(*outPath, strings.ReplaceAll(module.Path, "/", "_")+".out")
+		fmt.Printf("=== %s ===\n", module.Path)
+		//nolint:gosec // G204: Safe usage - the arguments are module paths of the workspace
+		cmd := exec.Command("go", "test", "-covermode=set", "-coverpkg="+strings.Join(coverPackages, ","), "-coverprofile="+profilePath, "./...")
+		cmd.Dir = filepath.Join(*folderPath, module.Folder)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Printf("Tests of %s failed: %v\n", module.Path, err)
+			testsFailed = true
+		}
+
+		if !fileExists(profilePath) {
+			continue
+		}
+		err = merged.add(profilePath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Println()
+
+	// Write the merged profile and the HTML report
+	mergedPath := filepath.Join(*outPath, "coverage.out")
+	err = merged.write(mergedPath)
+	if err != nil {
+		fmt.Println("Error writing the merged profile:", err)
+		os.Exit(1)
+	}
+	htmlPath := filepath.Join(*outPath, "coverage.html")
+	//nolint:gosec // G204: Safe usage - the paths are controlled by the application
+	cmd := exec.Command("go", "tool", "cover", "-html="+mergedPath, "-o", htmlPath)
+	cmd.Dir = *folderPath
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Println("Warning: the HTML report could not be created:", err)
+	} else {
+		fmt.Println("HTML report:", htmlPath)
+	}
+	fmt.Println("Merged profile:", mergedPath)
+	fmt.Println()
+
+	belowMinimum := printCoverage(merged, modules, cfg.Coverage)
+
+	if testsFailed {
+		fmt.Println("Tests failed.")
+	}
+	if belowMinimum > 0 {
+		fmt.Printf("%d module(s) below the minimum coverage.\n", belowMinimum)
+	}
+	if testsFailed || belowMinimum > 0 {
+		os.Exit(1)
+	}
+}
+
+// add merges the coverage profile in profilePath: counts are summed, except for mode "set"
+// where a block is covered if it is covered in any profile.
+func (profile *coverageProfile) add(profilePath string) error {
+	//nolint:gosec // G304: Safe usage - profilePath is created by the application
+	file, err := os.Open(profilePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if mode, ok := strings.CutPrefix(line, "mode: "); ok {
+			if profile.mode != "" && profile.mode != mode {
+				return fmt.Errorf("%s: coverage mode %s does not match %s", profilePath, mode, profile.mode)
+			}
+			profile.mode = mode
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return fmt.Errorf("%s: invalid line %q", profilePath, line)
+		}
+		key := strings.Join(fields[:len(fields)-2], " ")
+		statements, err1 := strconv.Atoi(fields[len(fields)-2])
+		count, err2 := strconv.Atoi(fields[len(fields)-1])
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("%s: invalid line %q", profilePath, line)
+		}
+
+		block, ok := profile.blocks[key]
+		if !ok {
+			profile.blocks[key] = &coverageBlock{statements: statements, count: count}
+			continue
+		}
+		if profile.mode == "set" {
+			block.count = max(block.count, count)
+		} else {
+			block.count += count
+		}
+	}
+	return scanner.Err()
+}
+
+// write writes the merged profile in the format of "go test -coverprofile".
+func (profile *coverageProfile) write(profilePath string) error {
+	keys := make([]string, 0, len(profile.blocks))
+	for key := range profile.blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	mode := profile.mode
+	if mode == "" {
+		mode = "set"
+	}
+	fmt.Fprintf(&builder, "mode: %s\n", mode)
+	for _, key := range keys {
+		block := profile.blocks[key]
+		fmt.Fprintf(&builder, "%s %d %d\n", key, block.statements, block.count)
+	}
+	return os.WriteFile(profilePath, []byte(builder.String()), 0o600)
+}
+
+// packageStats returns the coverage per package (import path) of the profile.
+func (profile *coverageProfile) packageStats() map[string]*coverageStats {
+	stats := make(map[string]*coverageStats)
+	for key, block := range profile.blocks {
+		fileName, _, _ := strings.Cut(key, ":")
+		packagePath := path.Dir(fileName)
+		if stats[packagePath] == nil {
+			stats[packagePath] = &coverageStats{}
+		}
+		stats[packagePath].statements += block.statements
+		if block.count > 0 {
+			stats[packagePath].covered += block.statements
+		}
+	}
+	return stats
+}
+
+// moduleOfPackage returns the path of the module containing packagePath (the longest matching module path).
+func moduleOfPackage(packagePath string, modules []*workspaceModule) string {
+	found := ""
+	for _, module := range modules {
+		if (packagePath == module.Path || strings.HasPrefix(packagePath, module.Path+"/")) && len(module.Path) > len(found) {
+			found = module.Path
+		}
+	}
+	return found
+}
+
+// printCoverage prints the coverage per module and per package and returns the number of modules
+// below their minimum.
+func printCoverage(profile *coverageProfile, modules []*workspaceModule, cfg coverageConfig) int {
+	packages := profile.packageStats()
+	packagePaths := make([]string, 0, len(packages))
+	moduleStats := make(map[string]*coverageStats, len(modules))
+	for packagePath, stats := range packages {
+		packagePaths = append(packagePaths, packagePath)
+		modulePath := moduleOfPackage(packagePath, modules)
+		if moduleStats[modulePath] == nil {
+			moduleStats[modulePath] = &coverageStats{}
+		}
+		moduleStats[modulePath].statements += stats.statements
+		moduleStats[modulePath].covered += stats.covered
+	}
+	sort.Strings(packagePaths)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PACKAGE\tSTATEMENTS\tCOVERAGE")
+	for _, packagePath := range packagePaths {
+		stats := packages[packagePath]
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", packagePath, stats.statements, stats)
+	}
+	_ = writer.Flush()
+	fmt.Println()
+
+	belowMinimum := 0
+	var total coverageStats
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODULE\tPATH\tSTATEMENTS\tCOVERAGE\tMINIMUM\tSTATUS")
+	for _, module := range modules {
+		stats := moduleStats[module.Path]
+		if stats == nil {
+			stats = &coverageStats{}
+		}
+		total.statements += stats.statements
+		total.covered += stats.covered
+
+		minimum, ok := cfg.Modules[module.Path]
+		if !ok {
+			minimum = cfg.Minimum
+		}
+		status := "ok"
+		minimumText := "-"
+		if minimum > 0 {
+			minimumText = fmt.Sprintf("%.1f%%", minimum)
+			// Modules without statements (e.g. only constants or types) have nothing to cover
+			if stats.statements > 0 && stats.percent() < minimum {
+				status = "BELOW MINIMUM"
+				belowMinimum++
+			}
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\t%s\n", module.Folder, module.Path, stats.statements, stats, minimumText, status)
+	}
+	_ = writer.Flush()
+
+	fmt.Println()
+	fmt.Printf("Total coverage: %s of %d statements\n", total, total.statements)
+	return belowMinimum
+}
@@ -1,6 +1,15 @@
 @echo off
+setlocal enabledelayedexpansion
 REM Cross-platform build script for Go projects
 REM Builds executables for Windows, Linux, and macOS
+REM
+REM Every package main of the module is built: the module root is named after the last part of the
+REM module path, commands in sub folders (e.g. cmd/server) after their folder.
+REM The targets can be configured per command in cross-build.conf:
+REM   default linux/amd64 windows/amd64     targets of all commands not listed
+REM   cmd/server linux/amd64 linux/arm64    targets of cmd/server
+REM
+REM Binaries are created in the bin\ directory as <command>-<os>-<arch>
 
 echo Building Go project for multiple platforms...
 echo.
@@ -15,64 +24,90 @@ REM Get module name from go.mod and extract the last part as binary name
 for /f "tokens=2" %%i in ('findstr /b "module " go.mod') do set MODULE_NAME=%%i
 for %%i in ("%MODULE_NAME:/=" "%") do set BINARY_NAME=%%~nxi
 
-echo Module: %MODULE_NAME%
-echo Binary name: %BINARY_NAME%
-echo.
-
-REM Create output directory
-if not exist "bin" mkdir bin
-
 REM Get version information from git
 FOR /F "tokens=* USEBACKQ" %%F IN (`git describe --tags`) DO (
 SET GIT_VERSION_INFO=%%F
 )
-ECHO %GIT_VERSION_INFO%
-
-REM Build for Windows (amd64)
-echo Building for Windows (amd64)...
-set GOOS=windows
-set GOARCH=amd64
-go build -ldflags "-X main.version=%GIT_VERSION_INFO%" -o bin\%BINARY_NAME%-windows-amd64.exe
-if %errorlevel% neq 0 (
-    echo Failed to build for Windows amd64
-    exit /b 1
+FOR /F "tokens=* USEBACKQ" %%D IN (`powershell -NoProfile -Command "(Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')"`) DO (
+SET BUILD_DATE=%%D
 )
 
-REM Build for Linux (amd64)
-echo Building for Linux (amd64)...
-set GOOS=linux
-set GOARCH=amd64
-go build -ldflags "-X main.version=%GIT_VERSION_INFO%" -o bin\%BINARY_NAME%-linux-amd64
-if %errorlevel% neq 0 (
-    echo Failed to build for Linux amd64
-    exit /b 1
-)
+REM Set the version of the internal/version package (and main.version for apps without that package)
+set LDFLAGS=-X main.version=%GIT_VERSION_INFO% -X %MODULE_NAME%/internal/version.Version=%GIT_VERSION_INFO% -X %MODULE_NAME%/internal/version.BuildDate=%BUILD_DATE%
+
+REM Default targets (a "default" line in cross-build.conf replaces them)
+set DEFAULT_TARGETS=windows/amd64 linux/amd64 darwin/amd64 darwin/arm64
+call :configured_targets default
+if defined TARGETS set DEFAULT_TARGETS=%TARGETS%
 
-REM Build for macOS (amd64 - Intel)
-echo Building for macOS (amd64 - Intel)...
-set GOOS=darwin
-set GOARCH=amd64
-go build -ldflags "-X main.version=%GIT_VERSION_INFO%" -o bin\%BINARY_NAME%-darwin-amd64
+REM Check that the packages of the module can be listed
+go list ./... >nul
 if %errorlevel% neq 0 (
-    echo Failed to build for macOS amd64
+    echo Failed to list the packages of the module
     exit /b 1
 )
 
-REM Build for macOS (arm64 - Apple Silicon)
-echo Building for macOS (arm64 - Apple Silicon)...
-set GOOS=darwin
-set GOARCH=arm64
-go build -ldflags "-X main.version=%GIT_VERSION_INFO%" -o bin\%BINARY_NAME%-darwin-arm64
-if %errorlevel% neq 0 (
-    echo Failed to build for macOS arm64
-    exit /b 1
+echo Module: %MODULE_NAME%
+echo Version: %GIT_VERSION_INFO%
+echo.
+
+REM Create output directory
+if not exist "bin" mkdir bin
+
+REM Build every command (package main) of the module
+set FOUND=
+set BINARIES=
+for /f "usebackq delims=" %%P in (`go list -f "{{if eq .Name \"main\"}}{{.ImportPath}}{{end}}" ./...`) do (
+    set FOUND=1
+    call :build_command %%P
+    if errorlevel 1 exit /b 1
+)
+if not defined FOUND (
+    echo No commands ^(package main^) found in %MODULE_NAME%, nothing to build
+    exit /b 0
 )
 
 echo.
 echo Build completed successfully!
 echo Binaries are located in the bin/ directory:
-echo   - %BINARY_NAME%-windows-amd64.exe (Windows)
-echo   - %BINARY_NAME%-linux-amd64 (Linux)
-echo   - %BINARY_NAME%-darwin-amd64 (macOS Intel)
-echo   - %BINARY_NAME%-darwin-arm64 (macOS Apple Silicon)
+for %%B in (%BINARIES%) do echo   - %%B
 echo.
+exit /b 0
+
+REM Build the command with the import path %1 for its targets
+:build_command
+set IMPORT_PATH=%~1
+if "%IMPORT_PATH%"=="%MODULE_NAME%" (
+    set COMMAND_DIR=.
+    set COMMAND_NAME=%BINARY_NAME%
+) else (
+    set COMMAND_DIR=!IMPORT_PATH:%MODULE_NAME%/=!
+    for %%N in ("!COMMAND_DIR:/=\!") do set COMMAND_NAME=%%~nxN
+)
+call :configured_targets %COMMAND_DIR%
+if not defined TARGETS set TARGETS=%DEFAULT_TARGETS%
+for %%T in (%TARGETS%) do (
+    for /f "tokens=1,2 delims=/" %%O in ("%%T") do (
+        set OUTPUT=!COMMAND_NAME!-%%O-%%P
+        if "%%O"=="windows" set OUTPUT=!OUTPUT!.exe
+        echo Building !COMMAND_DIR! for %%O ^(%%P^)...
+        set GOOS=%%O
+        set GOARCH=%%P
+        go build -ldflags "%LDFLAGS%" -o bin\!OUTPUT! .\!COMMAND_DIR:/=\!
+        if errorlevel 1 (
+            echo Failed to build !COMMAND_DIR! for %%O %%P
+            exit /b 1
+        )
+        set BINARIES=!BINARIES! !OUTPUT!
+    )
+)
+exit /b 0
+
+REM Set TARGETS to the targets of folder %1 in cross-build.conf (undefined if not configured)
+:configured_targets
+set TARGETS=
+if not exist "cross-build.conf" exit /b 0
+for /f "usebackq eol=# tokens=1,*" %%A in ("cross-build.conf") do (
+    if "%%A"=="%~1" if not defined TARGETS set TARGETS=%%B
+)
+exit /b 0
@@ -6,8 +6,14 @@
 #   chmod +x cross-build.sh
 #   ./cross-build.sh
 #
+# Every package main of the module is built: the module root is named after the last part of the
+# module path, commands in sub folders (e.g. cmd/server) after their folder.
+# The targets can be configured per command in cross-build.conf:
+#   default linux/amd64 windows/amd64     targets of all commands not listed
+#   cmd/server linux/amd64 linux/arm64    targets of cmd/server
+#
 # Output:
-#   Binaries are created in the bin/ directory
+#   Binaries are created in the bin/ directory as <command>-<os>-<arch>
 
 echo "Building Go project for multiple platforms..."
 echo ""
@@ -23,52 +29,72 @@ BINARY_NAME=$(basename "$MODULE_NAME")
 
 # Get version information from git
 VERSION=$(git describe --tags)
+BUILD_DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+
+# Set the version of the internal/version package (and main.version for apps without that package)
+LDFLAGS="-X main.version=$VERSION -X $MODULE_NAME/internal/version.Version=$VERSION -X $MODULE_NAME/internal/version.BuildDate=$BUILD_DATE"
+
+# configured_targets prints the targets of folder $1 from cross-build.conf (empty if not configured)
+configured_targets() {
+    if [ -f "cross-build.conf" ]; then
+        tr -d '\r' < cross-build.conf | awk -v dir="$1" '$1 == dir && NF > 1 { $1 = ""; print; exit }'
+    fi
+}
+
+DEFAULT_TARGETS=$(configured_targets default)
+DEFAULT_TARGETS=${DEFAULT_TARGETS:-windows/amd64 linux/amd64 darwin/amd64 darwin/arm64}
+
+# Find the commands (package main) of the module
+COMMAND_DIRS=$(go list -f '{{if eq .Name "main"}}{{.ImportPath}}{{end}}' ./...)
+if [ $? -ne 0 ]; then
+    echo "Failed to list the packages of the module"
+    exit 1
+fi
+if [ -z "$COMMAND_DIRS" ]; then
+    echo "No commands (package main) found in $MODULE_NAME, nothing to build"
+    exit 0
+fi
 
 echo "Module: $MODULE_NAME"
-echo "Binary name: $BINARY_NAME"
 echo "Version: $VERSION"
 echo ""
 
 # Create output directory
 mkdir -p bin
 
-# Build for Windows (amd64)
-echo "Building for Windows (amd64)..."
-GOOS=windows GOARCH=amd64 go build -ldflags "-X main.version=$VERSION" -o "bin/${BINARY_NAME}-windows-amd64.exe"
-if [ $? -ne 0 ]; then
-    echo "Failed to build for Windows amd64"
-    exit 1
-fi
-
-# Build for Linux (amd64)
-echo "Building for Linux (amd64)..."
-GOOS=linux GOARCH=amd64 go build -ldflags "-X main.version=$VERSION" -o "bin/${BINARY_NAME}-linux-amd64"
-if [ $? -ne 0 ]; then
-    echo "Failed to build for Linux amd64"
-    exit 1
-fi
+BINARIES=""
+for IMPORT_PATH in $COMMAND_DIRS; do
+    if [ "$IMPORT_PATH" = "$MODULE_NAME" ]; then
+        COMMAND_DIR="."
+        COMMAND_NAME=$BINARY_NAME
+    else
+        COMMAND_DIR=${IMPORT_PATH#"$MODULE_NAME"/}
+        COMMAND_NAME=$(basename "$COMMAND_DIR")
+    fi
 
-# Build for macOS (amd64 - Intel)
-echo "Building for macOS (amd64 - Intel)..."
-GOOS=darwin GOARCH=amd64 go build -ldflags "-X main.version=$VERSION" -o "bin/${BINARY_NAME}-darwin-amd64"
-if [ $? -ne 0 ]; then
-    echo "Failed to build for macOS amd64"
-    exit 1
-fi
+    TARGETS=$(configured_targets "$COMMAND_DIR")
+    for TARGET in ${TARGETS:-$DEFAULT_TARGETS}; do
+        TARGET_OS=${TARGET%/*}
+        TARGET_ARCH=${TARGET#*/}
+        OUTPUT="${COMMAND_NAME}-${TARGET_OS}-${TARGET_ARCH}"
+        if [ "$TARGET_OS" = "windows" ]; then
+            OUTPUT="$OUTPUT.exe"
+        fi
 
-# Build for macOS (arm64 - Apple Silicon)
-echo "Building for macOS (arm64 - Apple Silicon)..."
-GOOS=darwin GOARCH=arm64 go build -ldflags "-X main.version=$VERSION" -o "bin/${BINARY_NAME}-darwin-arm64"
-if [ $? -ne 0 ]; then
-    echo "Failed to build for macOS arm64"
-    exit 1
-fi
+        echo "Building $COMMAND_DIR for $TARGET_OS ($TARGET_ARCH)..."
+        GOOS=$TARGET_OS GOARCH=$TARGET_ARCH go build -ldflags "$LDFLAGS" -o "bin/$OUTPUT" "./$COMMAND_DIR"
+        if [ $? -ne 0 ]; then
+            echo "Failed to build $COMMAND_DIR for $TARGET_OS $TARGET_ARCH"
+            exit 1
+        fi
+        BINARIES="$BINARIES $OUTPUT"
+    done
+done
 
 echo ""
 echo "Build completed successfully!"
 echo "Binaries are located in the bin/ directory:"
-echo "  - ${BINARY_NAME}-windows-amd64.exe (Windows)"
-echo "  - ${BINARY_NAME}-linux-amd64 (Linux)"
-echo "  - ${BINARY_NAME}-darwin-amd64 (macOS Intel)"
-echo "  - ${BINARY_NAME}-darwin-arm64 (macOS Apple Silicon)"
+for OUTPUT in $BINARIES; do
+    echo "  - $OUTPUT"
+done
 echo ""
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// createDockerFiles creates the Dockerfile, .dockerignore and .devcontainer/devcontainer.json
+// for the application module in folderPath.
+func createDockerFiles(folderPath string) error {
+	mod, err := readGoMod(folderPath)
+	if err != nil {
+		return err
+	}
+	values, err := dockerTemplateValues(folderPath, mod)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(folderPath, "Dockerfile"), []byte(renderTemplate(dockerfileTemplate, values)), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating Dockerfile: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(folderPath, ".dockerignore"), []byte(dockerignoreTemplate), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating .dockerignore: %w", err)
+	}
+
+	devcontainerPath := filepath.Join(folderPath, ".devcontainer")
+	err = os.MkdirAll(devcontainerPath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating .devcontainer folder: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(devcontainerPath, "devcontainer.json"), []byte(renderTemplate(devcontainerJSONTemplate, values)), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating devcontainer.json: %w", err)
+	}
+	return nil
+}
+
+// dockerTemplateValues returns the placeholder values for the Docker templates of the module in
+// folderPath. All commands of the module are built; the entry point is the command named after the
+// module (the module root or cmd/<name>) or else the first command.
+func dockerTemplateValues(folderPath string, mod *goModFile) (map[string]string, error) {
+	appName := filepath.Base(mod.Module.Path)
+	dirs, err := mainPackageDirs(folderPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		dirs = []string{"."} // e.g. app nomain: main.go is added later
+	}
+
+	packages := make([]string, 0, len(dirs))
+	entrypoint := ""
+	for _, dir := range dirs {
+		name := path.Base(dir)
+		if dir == "." {
+			packages = append(packages, ".")
+			name = appName
+		} else {
+			packages = append(packages, "./"+dir)
+		}
+		if entrypoint == "" || name == appName {
+			entrypoint = name
+		}
+	}
+
+	return map[string]string{
+		"MODULE_NAME": mod.Module.Path,
+		"APP_NAME":    appName,
+		"GO_VERSION":  goMinorVersion(mod.Go),
+		"PACKAGES":    strings.Join(packages, " "),
+		"ENTRYPOINT":  entrypoint,
+	}, nil
+}
+
+// addDockerCommand adds the Docker files to an existing application.
+func addDockerCommand(folderPath string, force bool) {
+	if _, err := os.Stat(filepath.Join(folderPath, "Dockerfile")); err == nil && !force {
+		fmt.Println("Error: Dockerfile already exists (use 'force' to overwrite).")
+		os.Exit(1)
+	}
+
+	err := createDockerFiles(folderPath)
+	if err != nil {
+		fmt.Println("Error creating Docker files:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Dockerfile, .dockerignore and .devcontainer/devcontainer.json created successfully.")
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	editorVSCode = "vscode"
+	editorGoLand = "goland"
+	editorVim    = "vim"
+	editorNone   = "none"
+	editorCustom = "editor"
+)
+
+// editor describes the integration of an editor or IDE: the project files it needs and how it is launched.
+type editor struct {
+	// name is used for messages and the launcher file names (open_<name>.bat and open_<name>.sh)
+	name string
+	// command is the executable and its arguments, the target to open is appended
+	command []string
+	// terminal is set for editors running in the terminal instead of opening their own window
+	terminal bool
+	// createModuleFiles creates the project files for an app or lib (optional)
+	createModuleFiles func(folderPath, name string, isLibrary bool) error
+	// createWorkspaceFiles creates the project files for a workspace and returns the target to open (optional)
+	createWorkspaceFiles func(folderPath string, moduleFolders []string) (string, error)
+}
+
+// newEditor returns the editor integration for selection (vscode, goland, vim, none or a custom command).
+// nil is returned for "none".
+func newEditor(selection string) *editor {
+	switch selection {
+	case "", editorVSCode:
+		return &editor{
+			name:                 editorVSCode,
+			command:              []string{"code"},
+			createModuleFiles:    createVSCodeConfig,
+			createWorkspaceFiles: createVSCodeWorkspace,
+		}
+	case editorGoLand:
+		return &editor{
+			name:                 editorGoLand,
+			command:              []string{"goland"},
+			createModuleFiles:    createGoLandConfig,
+			createWorkspaceFiles: createGoLandWorkspace,
+		}
+	case editorVim:
+		return &editor{
+			name:     editorVim,
+			command:  []string{"vim"},
+			terminal: true,
+		}
+	case editorNone:
+		return nil
+	default:
+		return &editor{
+			name:    editorCustom,
+			command: strings.Fields(selection),
+		}
+	}
+}
+
+// selectEditor determines the editor from the --editor flag, the nocode option and the configuration.
+func selectEditor(editorFlag string, noCode bool, folderPath string) (*editor, error) {
+	if noCode {
+		return nil, nil
+	}
+	if editorFlag != "" {
+		return newEditor(editorFlag), nil
+	}
+	cfg, err := loadConfig(folderPath)
+	if err != nil {
+		return nil, err
+	}
+	return newEditor(cfg.Editor), nil
+}
+
+// setupModule creates the editor project files and the launcher for an app or lib.
+func (e *editor) setupModule(folderPath, name string, isLibrary bool) error {
+	if e.createModuleFiles != nil {
+		err := e.createModuleFiles(folderPath, name, isLibrary)
+		if err != nil {
+			return err
+		}
+	}
+	return e.createLauncher(folderPath, ".")
+}
+
+// setupWorkspace creates the editor project files and the launcher for a workspace.
+// It returns the target the editor opens.
+func (e *editor) setupWorkspace(folderPath string, moduleFolders []string) (string, error) {
+	target := "."
+	if e.createWorkspaceFiles != nil {
+		var err error
+		target, err = e.createWorkspaceFiles(folderPath, moduleFolders)
+		if err != nil {
+			return "", err
+		}
+	}
+	return target, e.createLauncher(folderPath, target)
+}
+
+// launcherFile returns the name of the launcher file with the given extension (".bat" or ".sh").
+func (e *editor) launcherFile(ext string) string {
+	return "open_" + e.name + ext
+}
+
+// createLauncher creates the launchers opening target (a folder or a project file) in the editor.
+func (e *editor) createLauncher(folderPath, target string) error {
+	commandLine := strings.Join(e.command, " ") + " \"" + target + "\""
+
+	batchFilePath := filepath.Join(folderPath, e.launcherFile(".bat"))
+	err1 := os.WriteFile(batchFilePath, []byte(commandLine+" | exit 0\n"), 0o600)
+	if err1 != nil {
+		err1 = fmt.Errorf("error creating %s: %w", e.launcherFile(".bat"), err1)
+	}
+
+	scriptFilePath := filepath.Join(folderPath, e.launcherFile(".sh"))
+	//nolint:gosec // G306: Script needs to be executable
+	err2 := os.WriteFile(scriptFilePath, []byte("#!/bin/bash\n"+commandLine+" || exit 0\n"), 0o700) // Make the script executable
+	if err2 != nil {
+		err2 = fmt.Errorf("error creating %s: %w", e.launcherFile(".sh"), err2)
+	}
+	return errors.Join(err1, err2)
+}
+
+// launch opens target in the editor. A missing editor or a headless environment is reported, but
+// is no error: the project files and launchers are already in place and can be used later.
+// Editors opening their own window are started without waiting for them to exit.
+func (e *editor) launch(folderPath, target string) {
+	if len(e.command) == 0 {
+		fmt.Println("No editor command configured => editor not opened.")
+		return
+	}
+
+	executable, err := exec.LookPath(e.command[0])
+	if err != nil {
+		fmt.Printf("Editor '%s' not found => not opened (use %s later).\n", e.command[0], e.launcherFile(launcherExt()))
+		return
+	}
+	if e.terminal && !isInteractiveTerminal() {
+		fmt.Printf("No interactive terminal => '%s' not opened.\n", e.command[0])
+		return
+	}
+	if !e.terminal && isHeadless() {
+		fmt.Printf("No graphical environment => '%s' not opened.\n", e.command[0])
+		return
+	}
+
+	//nolint:gosec // G204: Safe usage - the editor command is chosen by the user
+	cmd := exec.Command(executable, append(e.command[1:], target)...)
+	cmd.Dir = folderPath
+
+	fmt.Printf("Opening %s...\n", e.command[0])
+	if e.terminal {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+	} else {
+		err = cmd.Start()
+		if err == nil {
+			err = cmd.Process.Release()
+		}
+	}
+	if err != nil {
+		fmt.Printf("Warning: opening '%s' failed: %v\n", e.command[0], err)
+	}
+}
+
+// launcherExt returns the extension of the launcher for the current operating system.
+func launcherExt() string {
+	if runtime.GOOS == "windows" {
+		return ".bat"
+	}
+	return ".sh"
+}
+
+// isHeadless reports whether no graphical environment is available (CI or a Unix system without display).
+func isHeadless() bool {
+	if os.Getenv("CI") != "" {
+		return true
+	}
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		return false
+	default:
+		return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+	}
+}
+
+// isInteractiveTerminal reports whether stdin and stdout are connected to a terminal.
+func isInteractiveTerminal() bool {
+	return os.Getenv("CI") == "" && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether file is a character device other than the null device.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	nullInfo, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, nullInfo)
+}
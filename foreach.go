@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// foreachResult holds the outcome of running the command in a single workspace module.
+type foreachResult struct {
+	module   *workspaceModule
+	exitCode int
+	err      error
+}
+
+func printForeachUsage() {
+	fmt.Println("Usage: vasgotools.exe foreach [--path <workspace>] [--parallel <n>] [--filter <glob>] -- <command> [args...]")
+}
+
+func foreachCommand(args []string) {
+	// Define a flag set for the "foreach" command
+	fs := flag.NewFlagSet("foreach", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace (defaults to current working directory)")
+	parallel := fs.Int("parallel", 1, "Maximum number of modules the command runs in parallel")
+	filter := fs.String("filter", "", "Run the command only in modules whose folder or module path matches the glob pattern")
+	command, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+	if len(command) == 0 {
+		printForeachUsage()
+		os.Exit(1)
+	}
+	if *parallel < 1 {
+		*parallel = 1
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if *filter != "" {
+		modules, err = filterModules(modules, *filter)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+	if len(modules) == 0 {
+		fmt.Println("No modules found.")
+		return
+	}
+
+	results := runInModules(*folderPath, modules, command, *parallel)
+
+	var failed []*foreachResult
+	for _, result := range results {
+		if result.exitCode != 0 {
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Println()
+	if len(failed) == 0 {
+		fmt.Printf("Command succeeded in all %d module(s).\n", len(results))
+		return
+	}
+	fmt.Printf("Command failed in %d of %d module(s):\n", len(failed), len(results))
+	for _, result := range failed {
+		fmt.Printf("  %s (%s): exit code %d", result.module.Path, result.module.Folder, result.exitCode)
+		if result.err != nil {
+			fmt.Printf(" - %v", result.err)
+		}
+		fmt.Println()
+	}
+	os.Exit(1)
+}
+
+// filterModules returns the modules whose folder (with forward slashes) or module path matches pattern.
+func filterModules(modules []*workspaceModule, pattern string) ([]*workspaceModule, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid filter '%s': %w", pattern, err)
+	}
+
+	var filtered []*workspaceModule
+	for _, module := range modules {
+		folderMatch, _ := path.Match(pattern, filepath.ToSlash(module.Folder))
+		pathMatch, _ := path.Match(pattern, module.Path)
+		if folderMatch || pathMatch {
+			filtered = append(filtered, module)
+		}
+	}
+	return filtered, nil
+}
+
+// runInModules runs command in every module, at most parallel at a time. The output of each module
+// is prefixed with its module path.
+func runInModules(rootPath string, modules []*workspaceModule, command []string, parallel int) []*foreachResult {
+	results := make([]*foreachResult, len(modules))
+	semaphore := make(chan struct{}, parallel)
+	var outputMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, module := range modules {
+		results[i] = &foreachResult{module: module}
+		wg.Add(1)
+		go func(result *foreachResult) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			prefix := "[" + result.module.Path + "] "
+			stdout := &prefixWriter{writer: os.Stdout, prefix: prefix, mutex: &outputMutex}
+			stderr := &prefixWriter{writer: os.Stderr, prefix: prefix, mutex: &outputMutex}
+
+			//nolint:gosec // G204: Safe usage - the command is given by the user
+			cmd := exec.Command(command[0], command[1:]...)
+			cmd.Dir = filepath.Join(rootPath, result.module.Folder)
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			err := cmd.Run()
+			stdout.flush()
+			stderr.flush()
+
+			var exitErr *exec.ExitError
+			switch {
+			case err == nil:
+			case errors.As(err, &exitErr):
+				result.exitCode = exitErr.ExitCode()
+			default:
+				result.exitCode = -1
+				result.err = err
+			}
+		}(results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+// prefixWriter writes complete lines prefixed with prefix. Writers sharing a mutex do not
+// interleave their lines.
+type prefixWriter struct {
+	writer  io.Writer
+	prefix  string
+	mutex   *sync.Mutex
+	pending []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	index := bytes.LastIndexByte(w.pending, '\n')
+	if index < 0 {
+		return len(p), nil
+	}
+	lines := w.pending[:index+1]
+	w.pending = slices.Clone(w.pending[index+1:])
+	w.write(string(lines))
+	return len(p), nil
+}
+
+// flush writes the last line, if it is not terminated by a newline.
+func (w *prefixWriter) flush() {
+	if len(w.pending) > 0 {
+		w.write(string(w.pending) + "\n")
+		w.pending = nil
+	}
+}
+
+func (w *prefixWriter) write(lines string) {
+	var builder strings.Builder
+	for _, line := range strings.SplitAfter(lines, "\n") {
+		if line != "" {
+			builder.WriteString(w.prefix + line)
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	_, _ = io.WriteString(w.writer, builder.String())
+}
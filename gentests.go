@@ -0,0 +1,562 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// testParam is a parameter of a function for which tests are generated.
+type testParam struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+// testTarget is an exported function without (some of) its tests.
+type testTarget struct {
+	name         string
+	params       []testParam
+	results      []string
+	returnsError bool
+	test         bool
+	benchmark    bool
+	fuzz         bool
+}
+
+// reservedTestNames are the fields and variables of the generated tests that parameters must not use.
+var reservedTestNames = []string{"name", "want", "wantErr", "got", "err", "tt", "t", "b", "f"}
+
+// fuzzSeeds are the seed corpus entries of the types supported by fuzz tests (%s is the type).
+var fuzzSeeds = map[string][3]string{
+	"string":  {`""`, `"hello"`, `"\x00\xff"`},
+	"[]byte":  {`[]byte("")`, `[]byte("hello")`, `[]byte{0x00, 0xff}`},
+	"bool":    {"false", "true", "false"},
+	"int":     {"0", "1", "-1"},
+	"int8":    {"%s(0)", "%s(1)", "%s(-1)"},
+	"int16":   {"%s(0)", "%s(1)", "%s(-1)"},
+	"int32":   {"%s(0)", "%s(1)", "%s(-1)"},
+	"int64":   {"%s(0)", "%s(1)", "%s(-1)"},
+	"rune":    {"%s(0)", "'a'", "%s(-1)"},
+	"uint":    {"%s(0)", "%s(1)", "%s(42)"},
+	"uint8":   {"%s(0)", "%s(1)", "%s(255)"},
+	"byte":    {"%s(0)", "%s(1)", "%s(255)"},
+	"uint16":  {"%s(0)", "%s(1)", "%s(42)"},
+	"uint32":  {"%s(0)", "%s(1)", "%s(42)"},
+	"uint64":  {"%s(0)", "%s(1)", "%s(42)"},
+	"float32": {"%s(0)", "%s(1.5)", "%s(-1)"},
+	"float64": {"%s(0)", "%s(1.5)", "%s(-1)"},
+}
+
+func printGenUsage() {
+	fmt.Println("Usage: vasgotools.exe gen tests <package folder> [nobench] [nofuzz] [dryrun]")
+}
+
+// genCommand generates code for an existing module.
+func genCommand(args []string) {
+	if len(args) < 1 || args[0] != "tests" {
+		printGenUsage()
+		os.Exit(1)
+	}
+
+	// Define a flag set for the "gen tests" command
+	fs := flag.NewFlagSet("gen tests", flag.ExitOnError)
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	noBench := slices.Contains(positional, "nobench")
+	noFuzz := slices.Contains(positional, "nofuzz")
+	dryRun := slices.Contains(positional, "dryrun")
+	positional = slices.DeleteFunc(positional, func(arg string) bool {
+		return arg == "nobench" || arg == "nofuzz" || arg == "dryrun"
+	})
+	if len(positional) != 1 {
+		fmt.Println("Error: the package folder is required.")
+		printGenUsage()
+		os.Exit(1)
+	}
+
+	err = generateTests(positional[0], !noBench, !noFuzz, dryRun)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+}
+
+// generateTests writes test skeletons for the exported functions of the package in folderPath
+// that have no tests yet: one test file per source file (<file>_test.go, or <file>_gen_test.go
+// if that already exists).
+func generateTests(folderPath string, withBenchmarks, withFuzz, dryRun bool) error {
+	fset := token.NewFileSet()
+	sources, testNames, err := parsePackageFolder(fset, folderPath)
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no Go source files found in %s", folderPath)
+	}
+
+	useLoop := false
+	if mod, err := readGoMod(folderPath); err == nil {
+		useLoop = compareSemver("v"+goMinorVersion(mod.Go)+".0", "v1.24.0") >= 0
+	}
+
+	importNames := resolveImportNames(folderPath, sources)
+
+	fileNames := make([]string, 0, len(sources))
+	for fileName := range sources {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	generated := 0
+	for _, fileName := range fileNames {
+		file := sources[fileName]
+		targets := findTestTargets(file, testNames, withBenchmarks, withFuzz)
+		if len(targets) == 0 {
+			continue
+		}
+
+		content, err := renderTestFile(file, targets, importNames, useLoop)
+		if err != nil {
+			return fmt.Errorf("error generating the tests of %s: %w", fileName, err)
+		}
+
+		base := strings.TrimSuffix(fileName, ".go")
+		testFileName := base + "_test.go"
+		if fileExists(filepath.Join(folderPath, testFileName)) {
+			testFileName = base + "_gen_test.go"
+			if fileExists(filepath.Join(folderPath, testFileName)) {
+				return fmt.Errorf("%s and %s_test.go already exist, move the generated tests into %s_test.go first", testFileName, base, base)
+			}
+		}
+
+		if dryRun {
+			fmt.Printf("// === %s ===\n%s\n", testFileName, content)
+		} else {
+			err = os.WriteFile(filepath.Join(folderPath, testFileName), content, 0o600)
+			if err != nil {
+				return fmt.Errorf("error creating %s: %w", testFileName, err)
+			}
+		}
+		for _, target := range targets {
+			fmt.Printf("%s: %s%s\n", testFileName, target.name, describeTestTarget(target))
+		}
+		generated++
+	}
+
+	if generated == 0 {
+		fmt.Println("All exported functions already have tests.")
+	}
+	return nil
+}
+
+// parsePackageFolder parses the Go files in folderPath. It returns the source files of the package
+// by file name and the names of the functions declared in its test files.
+func parsePackageFolder(fset *token.FileSet, folderPath string) (map[string]*ast.File, map[string]bool, error) {
+	entries, err := os.ReadDir(folderPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %s: %w", folderPath, err)
+	}
+
+	sources := make(map[string]*ast.File)
+	testNames := make(map[string]bool)
+	packageName := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(folderPath, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if strings.HasSuffix(name, "_test.go") {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+					testNames[fn.Name.Name] = true
+				}
+			}
+			continue
+		}
+		if packageName == "" {
+			packageName = file.Name.Name
+		}
+		if file.Name.Name == packageName {
+			sources[name] = file
+		}
+	}
+	return sources, testNames, nil
+}
+
+// findTestTargets returns the exported functions of file that are missing a test, benchmark or
+// fuzz test. Generic functions are skipped.
+func findTestTargets(file *ast.File, testNames map[string]bool, withBenchmarks, withFuzz bool) []testTarget {
+	var targets []testTarget
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+			continue
+		}
+		if fn.Type.TypeParams != nil {
+			fmt.Printf("Skipping generic function %s.\n", fn.Name.Name)
+			continue
+		}
+
+		target := testTarget{name: fn.Name.Name}
+		for _, field := range fn.Type.Params.List {
+			typ := field.Type
+			variadic := false
+			if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+				typ = ellipsis.Elt
+				variadic = true
+			}
+			names := field.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{{Name: "_"}}
+			}
+			for _, name := range names {
+				paramName := name.Name
+				if paramName == "_" {
+					paramName = "arg" + strconv.Itoa(len(target.params))
+				}
+				if slices.Contains(reservedTestNames, paramName) {
+					paramName += "Arg"
+				}
+				target.params = append(target.params, testParam{name: paramName, typ: types.ExprString(typ), variadic: variadic})
+			}
+		}
+		if fn.Type.Results != nil {
+			for _, field := range fn.Type.Results.List {
+				for range max(len(field.Names), 1) {
+					target.results = append(target.results, types.ExprString(field.Type))
+				}
+			}
+		}
+		if n := len(target.results); n > 0 && target.results[n-1] == "error" {
+			target.results = target.results[:n-1]
+			target.returnsError = true
+		}
+
+		target.test = !testNames["Test"+target.name]
+		target.benchmark = withBenchmarks && !testNames["Benchmark"+target.name]
+		target.fuzz = withFuzz && !testNames["Fuzz"+target.name] && isFuzzable(target.params)
+		if target.test || target.benchmark || target.fuzz {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// isFuzzable reports whether a fuzz test can be generated: at least one string or []byte
+// parameter and only parameter types supported by fuzzing (besides context.Context).
+func isFuzzable(params []testParam) bool {
+	found := false
+	for _, param := range params {
+		_, supported := fuzzSeeds[param.typ]
+		switch {
+		case param.variadic:
+			return false
+		case param.typ == "string" || param.typ == "[]byte":
+			found = true
+		case param.typ == "context.Context":
+		case !supported:
+			return false
+		}
+	}
+	return found
+}
+
+// describeTestTarget lists the generated kinds of tests, e.g. " (test, benchmark, fuzz)".
+func describeTestTarget(target testTarget) string {
+	var kinds []string
+	if target.test {
+		kinds = append(kinds, "test")
+	}
+	if target.benchmark {
+		kinds = append(kinds, "benchmark")
+	}
+	if target.fuzz {
+		kinds = append(kinds, "fuzz")
+	}
+	return " (" + strings.Join(kinds, ", ") + ")"
+}
+
+// callArguments returns the arguments of the call of the function under test; prefix selects
+// the variables (e.g. "tt." for the fields of the test table).
+func callArguments(params []testParam, prefix string) string {
+	args := make([]string, 0, len(params))
+	for _, param := range params {
+		switch {
+		case param.typ == "context.Context":
+			args = append(args, "context.Background()")
+		case param.variadic:
+			args = append(args, prefix+param.name+"...")
+		default:
+			args = append(args, prefix+param.name)
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// resultNames returns the variables receiving the results: got, got1, ... and err.
+func resultNames(target testTarget, got string) []string {
+	names := make([]string, 0, len(target.results)+1)
+	for i := range target.results {
+		if i == 0 {
+			names = append(names, got)
+		} else {
+			names = append(names, got+strconv.Itoa(i))
+		}
+	}
+	if target.returnsError {
+		names = append(names, "err")
+	}
+	return names
+}
+
+// blankResults returns the assignment discarding the results of the call, e.g. "_, _ = ".
+func blankResults(target testTarget) string {
+	n := len(resultNames(target, "_"))
+	if n == 0 {
+		return ""
+	}
+	return strings.Repeat("_, ", n-1) + "_ = "
+}
+
+// renderTestFile returns the formatted test file for the targets of the source file. importNames
+// are the package names of the imports by import path (see resolveImportNames).
+func renderTestFile(file *ast.File, targets []testTarget, importNames map[string]string, useLoop bool) ([]byte, error) {
+	var body strings.Builder
+	for _, target := range targets {
+		if target.test {
+			renderTableTest(&body, target)
+		}
+		if target.benchmark {
+			renderBenchmark(&body, target, useLoop)
+		}
+		if target.fuzz {
+			renderFuzzTest(&body, target)
+		}
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "package %s\n\nimport (\n", file.Name.Name)
+	for _, spec := range testImports(file, targets, importNames, body.String()) {
+		fmt.Fprintf(&content, "\t%s\n", spec)
+	}
+	content.WriteString(")\n")
+	content.WriteString(body.String())
+	return format.Source([]byte(content.String()))
+}
+
+func renderTableTest(w *strings.Builder, target testTarget) {
+	fmt.Fprintf(w, "\nfunc Test%s(t *testing.T) {\n\ttests := []struct {\n\t\tname string\n", target.name)
+	for _, param := range target.params {
+		if param.typ == "context.Context" {
+			continue
+		}
+		typ := param.typ
+		if param.variadic {
+			typ = "[]" + typ
+		}
+		fmt.Fprintf(w, "\t\t%s %s\n", param.name, typ)
+	}
+	wants := resultNames(target, "want")
+	for i, result := range target.results {
+		fmt.Fprintf(w, "\t\t%s %s\n", wants[i], result)
+	}
+	if target.returnsError {
+		w.WriteString("\t\twantErr bool\n")
+	}
+	w.WriteString("\t}{\n\t\t// TODO: add test cases\n\t}\n\n")
+
+	w.WriteString("\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n\t\t\t")
+	gots := resultNames(target, "got")
+	if len(gots) > 0 {
+		fmt.Fprintf(w, "%s := ", strings.Join(gots, ", "))
+	}
+	fmt.Fprintf(w, "%s(%s)\n", target.name, callArguments(target.params, "tt."))
+	if target.returnsError {
+		fmt.Fprintf(w, "\t\t\tif (err != nil) != tt.wantErr {\n\t\t\t\tt.Fatalf(\"%s() error = %%v, wantErr %%v\", err, tt.wantErr)\n\t\t\t}\n", target.name)
+	}
+	for i := range target.results {
+		fmt.Fprintf(w, "\t\t\tif !reflect.DeepEqual(%s, tt.%s) {\n\t\t\t\tt.Errorf(\"%s() %s = %%v, want %%v\", %s, tt.%s)\n\t\t\t}\n",
+			gots[i], wants[i], target.name, gots[i], gots[i], wants[i])
+	}
+	w.WriteString("\t\t})\n\t}\n}\n")
+}
+
+func renderBenchmark(w *strings.Builder, target testTarget, useLoop bool) {
+	fmt.Fprintf(w, "\nfunc Benchmark%s(b *testing.B) {\n", target.name)
+	var vars []testParam
+	for _, param := range target.params {
+		if param.typ != "context.Context" {
+			vars = append(vars, param)
+		}
+	}
+	if len(vars) > 0 {
+		w.WriteString("\t// TODO: initialize the arguments with typical values\n\tvar (\n")
+		for _, param := range vars {
+			typ := param.typ
+			if param.variadic {
+				typ = "[]" + typ
+			}
+			fmt.Fprintf(w, "\t\t%s %s\n", param.name, typ)
+		}
+		w.WriteString("\t)\n\n")
+	}
+	if useLoop {
+		w.WriteString("\tfor b.Loop() {\n")
+	} else {
+		w.WriteString("\tfor i := 0; i < b.N; i++ {\n")
+	}
+	fmt.Fprintf(w, "\t\t%s%s(%s)\n\t}\n}\n", blankResults(target), target.name, callArguments(target.params, ""))
+}
+
+func renderFuzzTest(w *strings.Builder, target testTarget) {
+	fmt.Fprintf(w, "\nfunc Fuzz%s(f *testing.F) {\n", target.name)
+	var fuzzParams []testParam
+	for _, param := range target.params {
+		if param.typ != "context.Context" {
+			fuzzParams = append(fuzzParams, param)
+		}
+	}
+	for i := range 3 {
+		seeds := make([]string, 0, len(fuzzParams))
+		for _, param := range fuzzParams {
+			seed := fuzzSeeds[param.typ][i]
+			if strings.Contains(seed, "%s") {
+				seed = fmt.Sprintf(seed, param.typ)
+			}
+			seeds = append(seeds, seed)
+		}
+		fmt.Fprintf(w, "\tf.Add(%s)\n", strings.Join(seeds, ", "))
+	}
+
+	declarations := make([]string, 0, len(fuzzParams))
+	for _, param := range fuzzParams {
+		declarations = append(declarations, param.name+" "+param.typ)
+	}
+	fmt.Fprintf(w, "\tf.Fuzz(func(t *testing.T, %s) {\n", strings.Join(declarations, ", "))
+	w.WriteString("\t\t// TODO: check properties that hold for every input (no panic, round trip, invariants)\n")
+	fmt.Fprintf(w, "\t\t%s%s(%s)\n\t})\n}\n", blankResults(target), target.name, callArguments(target.params, ""))
+}
+
+// testImports returns the import specs of the test file: testing, reflect and context if used,
+// and the imports of the source file referenced by the parameter and result types.
+func testImports(file *ast.File, targets []testTarget, importNames map[string]string, body string) []string {
+	specs := []string{strconv.Quote("testing")}
+	if strings.Contains(body, "reflect.DeepEqual") {
+		specs = append(specs, strconv.Quote("reflect"))
+	}
+
+	used := make(map[string]bool)
+	for _, target := range targets {
+		for _, param := range target.params {
+			if param.typ == "context.Context" {
+				used["context"] = true
+			}
+			collectQualifiers(param.typ, used)
+		}
+		if target.test {
+			for _, result := range target.results {
+				collectQualifiers(result, used)
+			}
+		}
+	}
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name, ok := importNames[importPath]
+		if !ok {
+			name = packageNameFromModule(importPath)
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !used[name] || slices.Contains(specs, spec.Path.Value) {
+			continue
+		}
+		if spec.Name != nil {
+			specs = append(specs, name+" "+spec.Path.Value)
+		} else {
+			specs = append(specs, spec.Path.Value)
+		}
+		delete(used, name)
+	}
+	if used["context"] {
+		specs = append(specs, strconv.Quote("context"))
+	}
+	sort.Strings(specs)
+	return specs
+}
+
+// resolveImportNames returns the package names (package clause) of the imports of the source files
+// by import path, e.g. "yaml" for gopkg.in/yaml.v3, using "go list" in folderPath. Imports that
+// cannot be resolved are missing; their names are then derived from the import path.
+func resolveImportNames(folderPath string, sources map[string]*ast.File) map[string]string {
+	names := make(map[string]string)
+	var importPaths []string
+	for _, file := range sources {
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err == nil && !slices.Contains(importPaths, importPath) {
+				importPaths = append(importPaths, importPath)
+			}
+		}
+	}
+	if len(importPaths) == 0 {
+		return names
+	}
+
+	//nolint:gosec // G204: Safe usage - the arguments are the import paths of the parsed sources
+	cmd := exec.Command("go", append([]string{"list", "-e", "-f", "{{.ImportPath}} {{.Name}}"}, importPaths...)...)
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		return names
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		importPath, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && name != "" {
+			names[importPath] = name
+		}
+	}
+	return names
+}
+
+// collectQualifiers adds the package qualifiers of the type expression typ (e.g. "io" for io.Reader).
+func collectQualifiers(typ string, used map[string]bool) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return
+	}
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+)
+
+const goLandFolder = ".idea"
+
+const goLandModulesXML = `<?xml version="1.0" encoding="UTF-8"?>
+<project version="4">
+  <component name="ProjectModuleManager">
+    <modules>
+      <module fileurl="file://$PROJECT_DIR$/.idea/%[1]s.iml" filepath="$PROJECT_DIR$/.idea/%[1]s.iml" />
+    </modules>
+  </component>
+</project>
+`
+
+const goLandModuleIML = `<?xml version="1.0" encoding="UTF-8"?>
+<module type="WEB_MODULE" version="4">
+  <component name="Go" enabled="true" />
+  <component name="NewModuleRootManager">
+    <content url="file://$MODULE_DIR$">
+      <excludeFolder url="file://$MODULE_DIR$/bin" />
+    </content>
+    <orderEntry type="inheritedJdk" />
+    <orderEntry type="sourceFolder" forTests="false" />
+  </component>
+</module>
+`
+
+const goLandRunConfigurationXML = `<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="%[1]s" type="GoApplicationRunConfiguration" factoryName="Go Application">
+    <module name="%[1]s" />
+    <working_directory value="$PROJECT_DIR$" />
+    <kind value="DIRECTORY" />
+    <directory value="$PROJECT_DIR$" />
+    <filePath value="$PROJECT_DIR$" />
+    <method v="2" />
+  </configuration>
+</component>
+`
+
+const goLandTestConfigurationXML = `<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="Test %[1]s" type="GoTestRunConfiguration" factoryName="Go Test">
+    <module name="%[1]s" />
+    <working_directory value="$PROJECT_DIR$" />
+    <kind value="DIRECTORY" />
+    <package value="" />
+    <directory value="$PROJECT_DIR$" />
+    <filePath value="$PROJECT_DIR$" />
+    <framework value="gotest" />
+    <method v="2" />
+  </configuration>
+</component>
+`
+
+// createGoLandConfig creates the .idea folder with the project module and (for applications) a run configuration.
+func createGoLandConfig(folderPath, name string, isLibrary bool) error {
+	err := createGoLandProject(folderPath, name)
+	if err != nil {
+		return err
+	}
+
+	runConfigurationsPath := filepath.Join(folderPath, goLandFolder, "runConfigurations")
+	err = os.MkdirAll(runConfigurationsPath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating %s folder: %w", runConfigurationsPath, err)
+	}
+
+	escapedName := html.EscapeString(name)
+	err = writeGoLandFile(filepath.Join(runConfigurationsPath, "Test_"+name+".xml"), fmt.Sprintf(goLandTestConfigurationXML, escapedName))
+	if err != nil || isLibrary {
+		return err
+	}
+	return writeGoLandFile(filepath.Join(runConfigurationsPath, name+".xml"), fmt.Sprintf(goLandRunConfigurationXML, escapedName))
+}
+
+// createGoLandWorkspace creates the .idea folder for a workspace. GoLand picks up the modules from go.work,
+// so a single project module covering the workspace root is sufficient.
+func createGoLandWorkspace(folderPath string, _ []string) (string, error) {
+	return ".", createGoLandProject(folderPath, filepath.Base(folderPath))
+}
+
+// createGoLandProject creates the .idea folder with modules.xml and the project module file.
+func createGoLandProject(folderPath, name string) error {
+	ideaPath := filepath.Join(folderPath, goLandFolder)
+	err := os.MkdirAll(ideaPath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating %s folder: %w", goLandFolder, err)
+	}
+
+	err = writeGoLandFile(filepath.Join(ideaPath, "modules.xml"), fmt.Sprintf(goLandModulesXML, html.EscapeString(name)))
+	if err != nil {
+		return err
+	}
+	return writeGoLandFile(filepath.Join(ideaPath, name+".iml"), goLandModuleIML)
+}
+
+func writeGoLandFile(filePath, content string) error {
+	err := os.WriteFile(filePath, []byte(content), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Base(filePath), err)
+	}
+	return nil
+}
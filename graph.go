@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+	graphFormatJSON    = "json"
+)
+
+// graphNode is a module of the dependency graph.
+type graphNode struct {
+	Path string `json:"path"`
+	// Folder is the folder relative to the workspace root (workspace modules only)
+	Folder string `json:"folder,omitempty"`
+	// Kind is "app" or "lib" for workspace modules and "external" for external dependencies
+	Kind    string `json:"kind"`
+	Version string `json:"version,omitempty"`
+}
+
+// graphEdge is a require directive of a workspace module.
+type graphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Version string `json:"version,omitempty"`
+	// Cycle marks edges that are part of a dependency cycle
+	Cycle bool `json:"cycle,omitempty"`
+}
+
+// moduleGraph is the dependency graph of a workspace.
+type moduleGraph struct {
+	Nodes  []graphNode `json:"nodes"`
+	Edges  []graphEdge `json:"edges"`
+	Cycles [][]string  `json:"cycles,omitempty"`
+}
+
+func graphCommand(args []string) {
+	// Define a flag set for the "graph" command
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace (defaults to current working directory)")
+	format := fs.String("format", graphFormatDOT, "Output format: dot, mermaid or json")
+	outFile := fs.String("out", "", "File to write the graph to (defaults to stdout)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	withExternal := slices.Contains(positional, "external")
+
+	if *format != graphFormatDOT && *format != graphFormatMermaid && *format != graphFormatJSON {
+		fmt.Printf("Error: unknown format '%s' (use dot, mermaid or json)\n", *format)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	graph := buildModuleGraph(*folderPath, modules, withExternal)
+
+	err = writeGraph(*outFile, *format, graph)
+	if err != nil {
+		fmt.Println("Error writing graph:", err)
+		os.Exit(1)
+	}
+	if *outFile != "" {
+		fmt.Printf("Graph written to %s\n", *outFile)
+	}
+
+	// Report cycles (on stderr, to keep the graph on stdout clean)
+	for _, cycle := range graph.Cycles {
+		fmt.Fprintf(os.Stderr, "Dependency cycle: %s\n", strings.Join(cycle, " -> "))
+	}
+	if len(graph.Cycles) > 0 {
+		os.Exit(1)
+	}
+}
+
+// buildModuleGraph creates the graph of the workspace modules. If withExternal is set, the direct
+// external requirements are added with their versions.
+func buildModuleGraph(rootPath string, modules []*workspaceModule, withExternal bool) *moduleGraph {
+	graph := &moduleGraph{Nodes: []graphNode{}, Edges: []graphEdge{}}
+
+	cycles := findModuleCycles(modules)
+	cycleEdges := make(map[[2]string]bool)
+	for _, cycle := range cycles {
+		for i := 0; i < len(cycle)-1; i++ {
+			cycleEdges[[2]string{cycle[i], cycle[i+1]}] = true
+		}
+	}
+	graph.Cycles = cycles
+
+	workspacePaths := modulesByPath(modules)
+	external := make(map[string]graphNode)
+	for _, module := range modules {
+		kind := "lib"
+		commands, err := mainPackageDirs(filepath.Join(rootPath, module.Folder))
+		if err == nil && len(commands) > 0 {
+			kind = "app"
+		}
+		graph.Nodes = append(graph.Nodes, graphNode{Path: module.Path, Folder: filepath.ToSlash(module.Folder), Kind: kind})
+
+		for _, require := range module.Mod.Require {
+			if _, ok := workspacePaths[require.Path]; ok {
+				if require.Path != module.Path {
+					graph.Edges = append(graph.Edges, graphEdge{
+						From:  module.Path,
+						To:    require.Path,
+						Cycle: cycleEdges[[2]string{module.Path, require.Path}],
+					})
+				}
+				continue
+			}
+			if !withExternal || require.Indirect {
+				continue
+			}
+			// Different versions of the same module are separate nodes
+			id := require.Path + "@" + require.Version
+			external[id] = graphNode{Path: require.Path, Kind: "external", Version: require.Version}
+			graph.Edges = append(graph.Edges, graphEdge{From: module.Path, To: id, Version: require.Version})
+		}
+	}
+
+	ids := make([]string, 0, len(external))
+	for id := range external {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		graph.Nodes = append(graph.Nodes, external[id])
+	}
+	return graph
+}
+
+// nodeID returns the identifier used by the edges for node.
+func (node graphNode) nodeID() string {
+	if node.Kind == "external" {
+		return node.Path + "@" + node.Version
+	}
+	return node.Path
+}
+
+// writeGraph writes the graph in the given format to outFile (or stdout, if outFile is empty).
+// Mermaid graphs written to a Markdown file (.md) are enclosed in a mermaid code block.
+func writeGraph(outFile, format string, graph *moduleGraph) error {
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		//nolint:gosec // G304: Safe usage - the graph file is chosen by the user
+		file, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch format {
+	case graphFormatMermaid:
+		return writeGraphMermaid(out, graph, strings.EqualFold(filepath.Ext(outFile), ".md"))
+	case graphFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(graph)
+	default:
+		return writeGraphDOT(out, graph)
+	}
+}
+
+func writeGraphDOT(out io.Writer, graph *moduleGraph) error {
+	var builder strings.Builder
+	builder.WriteString("digraph workspace {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [shape=box];\n")
+	for _, node := range graph.Nodes {
+		switch node.Kind {
+		case "external":
+			fmt.Fprintf(&builder, "  %q [label=%q, style=dashed];\n", node.nodeID(), node.Path+"\n"+node.Version)
+		case "app":
+			fmt.Fprintf(&builder, "  %q [label=%q, style=\"rounded,bold\"];\n", node.nodeID(), node.Folder+"\n"+node.Path)
+		default:
+			fmt.Fprintf(&builder, "  %q [label=%q];\n", node.nodeID(), node.Folder+"\n"+node.Path)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.Cycle {
+			fmt.Fprintf(&builder, "  %q -> %q [color=red];\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&builder, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
+
+func writeGraphMermaid(out io.Writer, graph *moduleGraph, markdown bool) error {
+	// Mermaid node identifiers must not contain slashes or dots => number the nodes
+	ids := make(map[string]string, len(graph.Nodes))
+	var builder strings.Builder
+	if markdown {
+		builder.WriteString("```mermaid\n")
+	}
+	builder.WriteString("graph LR\n")
+	for i, node := range graph.Nodes {
+		id := fmt.Sprintf("m%d", i)
+		ids[node.nodeID()] = id
+		switch node.Kind {
+		case "external":
+			fmt.Fprintf(&builder, "  %s([\"%s<br/>%s\"])\n", id, node.Path, node.Version)
+		case "app":
+			fmt.Fprintf(&builder, "  %s[[\"%s<br/>%s\"]]\n", id, node.Folder, node.Path)
+		default:
+			fmt.Fprintf(&builder, "  %s[\"%s<br/>%s\"]\n", id, node.Folder, node.Path)
+		}
+	}
+	for i, edge := range graph.Edges {
+		fmt.Fprintf(&builder, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		if edge.Cycle {
+			fmt.Fprintf(&builder, "  linkStyle %d stroke:red\n", i)
+		}
+	}
+	if markdown {
+		builder.WriteString("```\n")
+	}
+
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
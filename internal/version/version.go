@@ -0,0 +1,98 @@
+// Package version provides the version information of the application.
+//
+// The version and the build date are set at build time by the build scripts:
+//
+//	go build -ldflags "-X <module>/internal/version.Version=v1.2.3 -X <module>/internal/version.BuildDate=2026-01-31T12:00:00Z"
+//
+// Without them (e.g. "go install" or "go run"), the information embedded by the Go toolchain is used.
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version and BuildDate are set at build time via -ldflags "-X ...".
+var (
+	Version   = ""
+	BuildDate = ""
+)
+
+const unknownVersion = "unknown version"
+
+// Info is the version information of the application.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Dirty     bool   `json:"dirty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+
+	// fromBuild is set if Version was set at build time, it already identifies the build
+	fromBuild bool
+}
+
+// Get returns the version information. Values set at build time take precedence over the build
+// information embedded by the Go toolchain.
+func Get() Info {
+	info := Info{Version: Version, BuildDate: BuildDate, GoVersion: runtime.Version(), fromBuild: Version != ""}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		if info.Version == "" {
+			info.Version = unknownVersion
+		}
+		return info
+	}
+
+	// Use the module version if available (from go install), local builds report "(devel)"
+	if info.Version == "" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+		info.Version = buildInfo.Main.Version
+	}
+	if info.Version == "" {
+		info.Version = unknownVersion
+	}
+
+	// Use the VCS information (Git) if available
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// ShortCommit returns the first 7 characters of the commit hash.
+func (info Info) ShortCommit() string {
+	if len(info.Commit) > 7 {
+		return info.Commit[:7]
+	}
+	return info.Commit
+}
+
+// String returns the version set at build time unchanged. Otherwise it returns the version with commit
+// and modification status, e.g. "v1.2.3 (commit: 1a2b3c4) [modified]".
+func (info Info) String() string {
+	s := info.Version
+	if !info.fromBuild && info.Commit != "" {
+		s = fmt.Sprintf("%s (commit: %s)", s, info.ShortCommit())
+		if info.Dirty {
+			s += " [modified]"
+		}
+	}
+	return s
+}
+
+// JSON returns the version information as indented JSON.
+func (info Info) JSON() string {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
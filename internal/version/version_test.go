@@ -0,0 +1,40 @@
+package version
+
+import "testing"
+
+func TestInfoString(t *testing.T) {
+	tests := []struct {
+		name string
+		info Info
+		want string
+	}{
+		{
+			name: "version set at build time",
+			info: Info{Version: "v1.2.3", Commit: "1a2b3c4d5e6f", Dirty: true, fromBuild: true},
+			want: "v1.2.3",
+		},
+		{
+			name: "module version",
+			info: Info{Version: "v1.2.3", Commit: "1a2b3c4d5e6f"},
+			want: "v1.2.3 (commit: 1a2b3c4)",
+		},
+		{
+			name: "modified local build",
+			info: Info{Version: unknownVersion, Commit: "1a2b3c4d5e6f", Dirty: true},
+			want: "unknown version (commit: 1a2b3c4) [modified]",
+		},
+		{
+			name: "without VCS information",
+			info: Info{Version: unknownVersion},
+			want: unknownVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	appKindBasic   = "basic"
+	appKindCLI     = "cli"
+	appKindService = "service"
+	appKindWorker  = "worker"
+)
+
+// validateAppKind returns an error for an unknown kind of application.
+func validateAppKind(kind string) error {
+	switch kind {
+	case appKindBasic, appKindCLI, appKindService, appKindWorker:
+		return nil
+	default:
+		return fmt.Errorf("unknown kind '%s' (use basic, cli, service or worker)", kind)
+	}
+}
+
+// appKindTemplates returns the templates of main.go and main_test.go for the kind of application.
+// The basic application has no test.
+func appKindTemplates(kind string) (mainGo, mainTestGo string) {
+	switch kind {
+	case appKindCLI:
+		return cliMainGoTemplate, cliMainTestGoTemplate
+	case appKindService:
+		return serviceMainGoTemplate, serviceMainTestGoTemplate
+	case appKindWorker:
+		return workerMainGoTemplate, workerMainTestGoTemplate
+	default:
+		return mainGoTemplate, ""
+	}
+}
+
+// createMainFiles creates main.go (and main_test.go) for the kind of application and the
+// internal/version package they use.
+func createMainFiles(folderPath, moduleName, kind string) error {
+	err := writeMainFiles(folderPath, moduleName, kind)
+	if err != nil {
+		return err
+	}
+	return createVersionPackage(folderPath)
+}
+
+// writeMainFiles writes main.go (and main_test.go) for the kind of application to folderPath.
+// moduleName is the path of the module containing the internal/version package.
+func writeMainFiles(folderPath, moduleName, kind string) error {
+	values := map[string]string{"MODULE_NAME": moduleName}
+	mainGo, mainTestGo := appKindTemplates(kind)
+
+	err := os.WriteFile(filepath.Join(folderPath, "main.go"), []byte(renderTemplate(mainGo, values)), 0o600)
+	if err != nil {
+		return err
+	}
+	if mainTestGo != "" {
+		err = os.WriteFile(filepath.Join(folderPath, "main_test.go"), []byte(renderTemplate(mainTestGo, values)), 0o600)
+		if err != nil {
+			return fmt.Errorf("error creating main_test.go: %w", err)
+		}
+	}
+	return nil
+}
+
+// createVersionPackage creates the internal/version package in the module in folderPath.
+func createVersionPackage(folderPath string) error {
+	versionPath := filepath.Join(folderPath, "internal", "version")
+	err := os.MkdirAll(versionPath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating internal/version folder: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(versionPath, "version.go"), []byte(versionPackageTemplate), 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating internal/version/version.go: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateAppKind(t *testing.T) {
+	for _, kind := range []string{appKindBasic, appKindCLI, appKindService, appKindWorker} {
+		if err := validateAppKind(kind); err != nil {
+			t.Errorf("validateAppKind(%s) error = %v", kind, err)
+		}
+	}
+	for _, kind := range []string{"", "CLI", "daemon"} {
+		if err := validateAppKind(kind); err == nil {
+			t.Errorf("validateAppKind(%q) error = nil, want an error", kind)
+		}
+	}
+}
+
+func TestCreateMainFilesCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go vet on the generated applications")
+	}
+
+	for _, kind := range []string{appKindBasic, appKindCLI, appKindService, appKindWorker} {
+		t.Run(kind, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := createMainFiles(dir, "example.com/app", kind); err != nil {
+				t.Fatalf("createMainFiles() error = %v", err)
+			}
+
+			cmd := exec.Command("go", "vet", "./...")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("go vet of the generated %s application failed: %v\n%s", kind, err, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// internalPackageName is the package created by the lib option "internal".
+const internalPackageName = "core"
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageNameFromModule derives a valid package name from a module path: the last path element
+// without major version suffix (/v2) and "go-" or "-go" affixes, lowercased and reduced to letters
+// and digits, e.g. "github.com/mbbm-slb/go-Signal_Tools/v2" becomes "signaltools". Keywords, "main"
+// and "doc" (whose starter file would replace doc.go) get the suffix "lib".
+func packageNameFromModule(modulePath string) string {
+	elements := strings.Split(modulePath, "/")
+	name := elements[len(elements)-1]
+	if majorVersionSuffix.MatchString(name) && len(elements) > 1 {
+		name = elements[len(elements)-2]
+	}
+	name = strings.ToLower(name)
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	var builder strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+		}
+	}
+	name = builder.String()
+
+	switch {
+	case name == "":
+		return "lib"
+	case name[0] >= '0' && name[0] <= '9':
+		return "lib" + name
+	case token.IsKeyword(name) || name == "main" || name == "doc":
+		return name + "lib"
+	}
+	return name
+}
+
+// createLibraryFiles creates the package files of a library (see createPackageFiles) and with
+// withInternal the internal/core package.
+func createLibraryFiles(folderPath, moduleName string, withInternal bool) (string, error) {
+	packageName, err := createPackageFiles(folderPath, moduleName)
+	if err != nil {
+		return "", err
+	}
+
+	if withInternal {
+		internalPath := filepath.Join(folderPath, "internal", internalPackageName)
+		err := os.MkdirAll(internalPath, 0o750)
+		if err != nil {
+			return "", fmt.Errorf("error creating internal/%s folder: %w", internalPackageName, err)
+		}
+		values := map[string]string{"MODULE_NAME": moduleName, "PACKAGE_NAME": internalPackageName}
+		err = os.WriteFile(filepath.Join(internalPath, "doc.go"), []byte(renderTemplate(libInternalDocGoTemplate, values)), 0o600)
+		if err != nil {
+			return "", fmt.Errorf("error creating internal/%s/doc.go: %w", internalPackageName, err)
+		}
+	}
+	return packageName, nil
+}
+
+// createPackageFiles creates doc.go, the starter source file, its table-driven test and an example
+// test (<package>_example_test.go) for the package with the import path importPath in folderPath. The name of the package is returned.
+func createPackageFiles(folderPath, importPath string) (string, error) {
+	packageName := packageNameFromModule(importPath)
+	values := map[string]string{"MODULE_NAME": importPath, "PACKAGE_NAME": packageName}
+
+	files := []struct {
+		name     string
+		template string
+	}{
+		{"doc.go", libDocGoTemplate},
+		{packageName + ".go", libGoTemplate},
+		{packageName + "_test.go", libTestGoTemplate},
+		{packageName + "_example_test.go", libExampleTestGoTemplate},
+	}
+	for _, file := range files {
+		err := os.WriteFile(filepath.Join(folderPath, file.name), []byte(renderTemplate(file.template, values)), 0o600)
+		if err != nil {
+			return "", fmt.Errorf("error creating %s: %w", file.name, err)
+		}
+	}
+	return packageName, nil
+}
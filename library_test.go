@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestPackageNameFromModule(t *testing.T) {
+	tests := []struct {
+		modulePath string
+		want       string
+	}{
+		{"github.com/x/signal", "signal"},
+		{"github.com/x/go-signal-tools", "signaltools"},
+		{"github.com/x/signal-go", "signal"},
+		{"github.com/x/Signal_Tools", "signaltools"},
+		{"github.com/x/signal/v2", "signal"},
+		{"my.lib", "mylib"},
+		{"123abc", "lib123abc"},
+		{"doc", "doclib"},
+		{"example.com/main", "mainlib"},
+		{"example.com/type", "typelib"},
+		{"example.com/---", "lib"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			if got := packageNameFromModule(tt.modulePath); got != tt.want {
+				t.Errorf("packageNameFromModule(%s) = %s, want %s", tt.modulePath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	licenseUnknown        = "Unknown"
+	licenseStatusOK       = "ok"
+	licenseStatusDenied   = "disallowed"
+	licenseStatusUnknown  = "unknown"
+	licenseStatusExcepted = "exception"
+	thirdPartyNoticesFile = "THIRD_PARTY_NOTICES.txt"
+)
+
+// moduleLicense describes the detected license of a third-party module.
+type moduleLicense struct {
+	Module      string `json:"module"`
+	Version     string `json:"version"`
+	License     string `json:"license"`
+	LicenseFile string `json:"licenseFile"`
+	Status      string `json:"status"`
+	text        string
+}
+
+// licenseFilePattern matches the names of files that usually contain the license of a module.
+var licenseFilePattern = regexp.MustCompile(`(?i)^(LICEN[CS]E|COPYING)([.\-_].*)?$`)
+
+// licenseMatcher maps a license identifier to the phrases that identify its license text.
+// The matchers are checked in order, so more specific licenses must come first.
+type licenseMatcher struct {
+	id      string
+	phrases []string
+}
+
+// licenseMatchers identify the GNU licenses by their title line first: their texts refer to each
+// other (the GPL-3.0 in section 13 to the GNU Affero General Public License, the MPL-2.0 to all
+// of them). The phrases without title only match the license notices in source files or READMEs.
+var licenseMatchers = []licenseMatcher{
+	{"AGPL-3.0", []string{"gnu affero general public license version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license version 3"}},
+	{"GPL-2.0", []string{"gnu general public license version 2"}},
+	{"MPL-2.0", []string{"mozilla public license version 2.0"}},
+	{"AGPL-3.0", []string{"gnu affero general public license"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"SSPL-1.0", []string{"server side public license"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"EPL-2.0", []string{"eclipse public license", "2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "endorse or promote products derived from this software"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+}
+
+func licensesCommand(args []string) {
+	// Define a flag set for the "licenses" command
+	fs := flag.NewFlagSet("licenses", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module or workspace (defaults to current working directory)")
+	format := fs.String("format", "csv", "Output format of the license report: csv or json")
+	outFile := fs.String("out", "", "File to write the license report to (defaults to stdout)")
+	noticesFile := fs.String("notices", filepath.Join("bin", thirdPartyNoticesFile), "File to write the third party notices to (relative to --path)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	noNotices := slices.Contains(positional, "nonotices")
+
+	if *format != "csv" && *format != "json" {
+		fmt.Printf("Error: unknown format '%s' (use csv or json)\n", *format)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	cfg, err := loadConfig(*folderPath)
+	if err != nil {
+		fmt.Println("Error loading configuration:", err)
+		os.Exit(1)
+	}
+
+	// Resolve the module graph (go list uses the go.work file automatically, if present)
+	modules, err := listModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	licenses := detectModuleLicenses(*folderPath, modules, &cfg.Licenses)
+
+	// Write the license report
+	err = writeLicenseReport(*outFile, *format, licenses)
+	if err != nil {
+		fmt.Println("Error writing license report:", err)
+		os.Exit(1)
+	}
+
+	// Write the third party notices (if not suppressed)
+	if !noNotices {
+		noticesPath := *noticesFile
+		if !filepath.IsAbs(noticesPath) {
+			noticesPath = filepath.Join(*folderPath, noticesPath)
+		}
+		err = writeThirdPartyNotices(noticesPath, licenses)
+		if err != nil {
+			fmt.Println("Error writing third party notices:", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Third party notices written to %s\n", noticesPath)
+	}
+
+	// Report policy violations (on stderr, to keep the report on stdout clean)
+	violations := 0
+	for _, license := range licenses {
+		if license.Status == licenseStatusDenied || license.Status == licenseStatusUnknown {
+			fmt.Fprintf(os.Stderr, "License policy violation: %s@%s (%s)\n", license.Module, license.Version, license.License)
+			violations++
+		}
+	}
+	if violations > 0 {
+		fmt.Fprintf(os.Stderr, "%d module(s) violate the license policy.\n", violations)
+		os.Exit(1)
+	}
+}
+
+// detectModuleLicenses detects the licenses of all third-party modules and applies the license policy.
+// The main modules (i.e. the module or the modules of the workspace itself) are skipped.
+func detectModuleLicenses(folderPath string, modules []listedModule, policy *licensesConfig) []moduleLicense {
+	var licenses []moduleLicense
+	for _, module := range modules {
+		if module.Main {
+			continue
+		}
+
+		license := moduleLicense{Module: module.Path, Version: module.Version, License: licenseUnknown}
+		dir, err := moduleDir(folderPath, module)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+		} else {
+			license.License, license.LicenseFile, license.text = detectLicense(dir)
+		}
+		license.Status = licenseStatus(&license, policy)
+		licenses = append(licenses, license)
+	}
+	return licenses
+}
+
+// licenseStatus applies the license policy to a detected license.
+func licenseStatus(license *moduleLicense, policy *licensesConfig) string {
+	switch {
+	case slices.Contains(policy.Exceptions, license.Module):
+		return licenseStatusExcepted
+	case slices.Contains(policy.Disallowed, license.License):
+		return licenseStatusDenied
+	case license.License == licenseUnknown && !policy.AllowUnknown:
+		return licenseStatusUnknown
+	default:
+		return licenseStatusOK
+	}
+}
+
+// detectLicense searches the license file in the module folder dir and determines its license type.
+// It returns the license identifier, the name of the license file and the license text.
+func detectLicense(dir string) (id, fileName, text string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return licenseUnknown, "", ""
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !licenseFilePattern.MatchString(entry.Name()) {
+			continue
+		}
+		//nolint:gosec // G304: Safe usage - dir is a folder of the module cache
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		return classifyLicense(string(content)), entry.Name(), string(content)
+	}
+	return licenseUnknown, "", ""
+}
+
+// classifyLicense determines the license identifier from a license text.
+func classifyLicense(text string) string {
+	// Normalize the whitespace, license texts are wrapped at arbitrary positions
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	for _, matcher := range licenseMatchers {
+		matches := true
+		for _, phrase := range matcher.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return matcher.id
+		}
+	}
+	return licenseUnknown
+}
+
+// writeLicenseReport writes the license report in the given format to outFile (or stdout, if outFile is empty).
+func writeLicenseReport(outFile, format string, licenses []moduleLicense) error {
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		//nolint:gosec // G304: Safe usage - the report file is chosen by the user
+		file, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if format == "json" {
+		return writeLicensesJSON(out, licenses)
+	}
+	return writeLicensesCSV(out, licenses)
+}
+
+func writeLicensesCSV(out io.Writer, licenses []moduleLicense) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"module", "version", "license", "license_file", "status"})
+	if err != nil {
+		return err
+	}
+	for _, license := range licenses {
+		err = writer.Write([]string{license.Module, license.Version, license.License, license.LicenseFile, license.Status})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeLicensesJSON(out io.Writer, licenses []moduleLicense) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if licenses == nil {
+		licenses = []moduleLicense{}
+	}
+	return encoder.Encode(licenses)
+}
+
+// writeThirdPartyNotices writes the license texts of all third-party modules to a single file.
+func writeThirdPartyNotices(noticesPath string, licenses []moduleLicense) error {
+	var builder strings.Builder
+	builder.WriteString("THIRD PARTY NOTICES\n")
+	builder.WriteString("===================\n\n")
+	builder.WriteString("This software includes the following third-party modules.\n")
+
+	for _, license := range licenses {
+		builder.WriteString("\n")
+		builder.WriteString(strings.Repeat("-", 80))
+		builder.WriteString("\n")
+		fmt.Fprintf(&builder, "%s %s\n", license.Module, license.Version)
+		fmt.Fprintf(&builder, "License: %s\n", license.License)
+		builder.WriteString(strings.Repeat("-", 80))
+		builder.WriteString("\n\n")
+		if license.text != "" {
+			builder.WriteString(strings.TrimSpace(license.text))
+			builder.WriteString("\n")
+		} else {
+			builder.WriteString("No license text found.\n")
+		}
+	}
+
+	err := os.MkdirAll(filepath.Dir(noticesPath), 0o750)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(noticesPath, []byte(builder.String()), 0o600)
+}
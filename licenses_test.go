@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassifyLicense(t *testing.T) {
+	// The license texts in testdata/licenses are the published texts (GPL, LGPL, Apache and MPL
+	// from /usr/share/common-licenses, MIT, ISC and BSD from modules in the module cache)
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"AGPL-3.0.txt", "AGPL-3.0"},
+		{"GPL-3.0.txt", "GPL-3.0"},
+		{"GPL-2.0.txt", "GPL-2.0"},
+		{"LGPL-3.0.txt", "LGPL-3.0"},
+		{"LGPL-2.1.txt", "LGPL-2.1"},
+		{"MPL-2.0.txt", "MPL-2.0"},
+		{"Apache-2.0.txt", "Apache-2.0"},
+		{"MIT.txt", "MIT"},
+		{"ISC.txt", "ISC"},
+		{"BSD-3-Clause.txt", "BSD-3-Clause"},
+		{"BSD-3-Clause-contributors.txt", "BSD-3-Clause"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", "licenses", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := classifyLicense(string(text)); got != tt.want {
+				t.Errorf("classifyLicense() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyLicenseNotice(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "GPL-3.0 notice",
+			text: `This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.`,
+			want: "GPL-3.0",
+		},
+		{
+			name: "AGPL-3.0 notice",
+			text: `This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.`,
+			want: "AGPL-3.0",
+		},
+		{
+			name: "LGPL-2.1 notice",
+			text: `This library is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 2.1 of the License, or (at your option) any later version.`,
+			want: "LGPL-2.1",
+		},
+		{
+			name: "BSD-2-Clause",
+			text: `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+1. Redistributions of source code must retain the above copyright notice.
+2. Redistributions in binary form must reproduce the above copyright notice.`,
+			want: "BSD-2-Clause",
+		},
+		{
+			name: "unknown",
+			text: "All rights reserved.",
+			want: licenseUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyLicense(tt.text); got != tt.want {
+				t.Errorf("classifyLicense() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,16 +10,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
-	"runtime"
-	"runtime/debug"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/mbbm-slb/vasgotools/internal/version"
 )
 
 const (
-	openVSCodeBatchFile = "open_vscode.bat"
-	openVSCodeShellFile = "open_vscode.sh"
 	modulePrefixMbbVas  = "github.com/muellerbbm-vas/"
 	modulePrefixMbbmSlb = "github.com/mbbm-slb/"
 )
@@ -41,6 +41,63 @@ var crossBuildShTemplate string
 //go:embed main.go.template
 var mainGoTemplate string
 
+//go:embed internal/version/version.go
+var versionPackageTemplate string
+
+//go:embed cli.main.go.template
+var cliMainGoTemplate string
+
+//go:embed cli.main_test.go.template
+var cliMainTestGoTemplate string
+
+//go:embed service.main.go.template
+var serviceMainGoTemplate string
+
+//go:embed service.main_test.go.template
+var serviceMainTestGoTemplate string
+
+//go:embed service.systemd.template
+var systemdUnitTemplate string
+
+//go:embed service.env.template
+var serviceEnvTemplate string
+
+//go:embed service.install.sh.template
+var serviceInstallShTemplate string
+
+//go:embed service.uninstall.sh.template
+var serviceUninstallShTemplate string
+
+//go:embed service.winsw.xml.template
+var serviceWinSWXMLTemplate string
+
+//go:embed service.install.ps1.template
+var serviceInstallPs1Template string
+
+//go:embed service.uninstall.ps1.template
+var serviceUninstallPs1Template string
+
+//go:embed worker.main.go.template
+var workerMainGoTemplate string
+
+//go:embed worker.main_test.go.template
+var workerMainTestGoTemplate string
+
+//go:embed lib.doc.go.template
+var libDocGoTemplate string
+
+//go:embed lib.go.template
+var libGoTemplate string
+
+//go:embed lib_test.go.template
+var libTestGoTemplate string
+
+//go:embed lib.example_test.go.template
+var libExampleTestGoTemplate string
+
+//go:embed lib.internal.doc.go.template
+var libInternalDocGoTemplate string
+
 //go:embed golangci_win.yml
 var golangciWinYmlTemplate string
 
@@ -50,6 +107,51 @@ var golangciYmlTemplate string
 //go:embed LICENSE
 var licenseTemplate string
 
+//go:embed CHANGELOG.md.template
+var changelogTemplate string
+
+//go:embed Dockerfile.template
+var dockerfileTemplate string
+
+//go:embed dockerignore.template
+var dockerignoreTemplate string
+
+//go:embed devcontainer.json.template
+var devcontainerJSONTemplate string
+
+//go:embed Jenkinsfile.template
+var jenkinsfileTemplate string
+
+//go:embed Jenkinsfile.work.template
+var jenkinsfileWorkTemplate string
+
+//go:embed work-build.bat
+var workBuildBatTemplate string
+
+//go:embed work-build.sh
+var workBuildShTemplate string
+
+//go:embed ci.github.template
+var ciGitHubTemplate string
+
+//go:embed ci.github.work.template
+var ciGitHubWorkTemplate string
+
+//go:embed ci.gitlab.template
+var ciGitLabTemplate string
+
+//go:embed ci.gitlab.work.template
+var ciGitLabWorkTemplate string
+
+// renderTemplate replaces the {{KEY}} placeholders in a template with the given values.
+func renderTemplate(template string, values map[string]string) string {
+	oldNew := make([]string, 0, 2*len(values))
+	for key, value := range values {
+		oldNew = append(oldNew, "{{"+key+"}}", value)
+	}
+	return strings.NewReplacer(oldNew...).Replace(template)
+}
+
 func main() {
 	// Ensure a subcommand is provided
 	if len(os.Args) < 2 {
@@ -60,16 +162,48 @@ func main() {
 	// Determine the subcommand
 	switch os.Args[1] {
 	case "work":
-		generateWorkCommand(os.Args[2:])
+		workCommand(os.Args[2:])
 	case "app":
 		generateModuleCommand(os.Args[2:], false)
 	case "lib":
 		generateModuleCommand(os.Args[2:], true)
+	case "foreach":
+		foreachCommand(os.Args[2:])
+	case "graph":
+		graphCommand(os.Args[2:])
+	case "release":
+		releaseCommand(os.Args[2:])
+	case "apidiff":
+		apidiffCommand(os.Args[2:])
+	case "changelog":
+		changelogCommand(os.Args[2:])
+	case "add":
+		addCommand(os.Args[2:])
+	case "gen":
+		genCommand(os.Args[2:])
+	case "coverage":
+		coverageCommand(os.Args[2:])
+	case "bench":
+		benchCommand(os.Args[2:])
+	case "vendor":
+		vendorCommand(os.Args[2:])
+	case "mirror":
+		mirrorCommand(os.Args[2:])
+	case "postbuild":
+		postbuildCommand(os.Args[2:])
+	case "licenses":
+		licensesCommand(os.Args[2:])
+	case "sbom":
+		sbomCommand(os.Args[2:])
 	case "help", "--help", "-h":
 		printUsage()
 		os.Exit(0)
 	case "version", "--version", "-v":
-		fmt.Println("Version: ", getVersionString())
+		if slices.Contains(os.Args[2:], "--json") {
+			fmt.Println(version.Get().JSON())
+		} else {
+			fmt.Println("Version: ", getVersionString())
+		}
 		os.Exit(0)
 	default:
 		fmt.Printf("Unknown command: %s\n", os.Args[1])
@@ -78,49 +212,9 @@ func main() {
 	}
 }
 
-// version is set at build time via -ldflags "-X main.version=..."
-var version = "unknown version"
-
+// getVersionString returns the version of vasgotools (see the internal/version package).
 func getVersionString() string {
-	// If version was set at build time, return it directly
-	if version != "unknown version" {
-		return version
-	}
-
-	// Fallback: Try to read build info from the binary
-	if info, ok := debug.ReadBuildInfo(); ok {
-		versionString := "unknown version"
-
-		// Use the module version if available (from go install)
-		if info.Main.Version != "" && info.Main.Version != "(devel)" {
-			versionString = info.Main.Version
-		}
-
-		// Try to get version from VCS info (Git)
-		var revision, modified string
-		for _, setting := range info.Settings {
-			switch setting.Key {
-			case "vcs.revision":
-				revision = setting.Value
-			case "vcs.modified":
-				modified = setting.Value
-			}
-		}
-
-		// Append VCS info if available
-		if revision != "" {
-			shortRev := revision
-			if len(revision) > 7 {
-				shortRev = revision[:7]
-			}
-			versionString = fmt.Sprintf("%s (commit: %s)", versionString, shortRev)
-			if modified == "true" {
-				versionString += " [modified]"
-			}
-		}
-		return versionString
-	}
-	return version
+	return version.Get().String()
 }
 
 func printUsage() {
@@ -136,16 +230,135 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Available commands:")
 	fmt.Println("  work    Generate a Go workspace (i.e., a go.work file)")
+	fmt.Println("  work build")
+	fmt.Println("          Build all modules of a workspace in dependency order")
+	fmt.Println("  work skew")
+	fmt.Println("          Report (and align) dependencies required in different versions by the workspace modules")
+	fmt.Println("  foreach Run a command in every module of a workspace")
+	fmt.Println("  apidiff Compare the exported API of a module with its last release and recommend the next version")
+	fmt.Println("  graph   Show the dependencies between the modules of a workspace (DOT, Mermaid or JSON)")
+	fmt.Println("  release Tag the changed modules of a workspace in dependency order and update their dependents")
 	fmt.Println("  app     Create a new Go application")
 	fmt.Println("  lib     Create a new Go library")
+	fmt.Println("  add     Add an item to an existing module (docker, pkg <path>, cmd <name>)")
+	fmt.Println("  gen tests")
+	fmt.Println("          Generate table-driven tests, benchmarks and fuzz tests for the untested functions of a package")
+	fmt.Println("  coverage")
+	fmt.Println("          Run the tests of all workspace modules with merged coverage, HTML report and minimums")
+	fmt.Println("  bench   Run the benchmarks of a module or workspace, store them by commit and compare with a baseline")
+	fmt.Println("  vendor  Create or update (and verify) the vendor folder of a module or workspace for offline builds")
+	fmt.Println("  mirror  Export the required modules from the module cache as GOPROXY folder for air-gapped builds (export, verify)")
+	fmt.Println("  postbuild")
+	fmt.Println("          Lay the service files (systemd unit, Windows service) next to the cross-build output")
+	fmt.Println("  changelog")
+	fmt.Println("          Add entries to CHANGELOG.md, release the unreleased changes or check the file (add, release, check)")
+	fmt.Println("  licenses")
+	fmt.Println("          Audit the licenses of all third-party modules of a module or workspace")
+	fmt.Println("  sbom    Create a software bill of materials (CycloneDX or SPDX JSON) for an application")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  --path <path>        Specify the folder path (defaults to the current working directory)")
 	fmt.Println("  --module-prefix <prefix>")
 	fmt.Println("                      Specify the module prefix (default: github.com/muellerbbm-vas/)")
 	fmt.Println("  nogit                Skip Git repository initialization")
-	fmt.Println("  nocode               Skip creation and execution of the open_vscode file")
+	fmt.Println("  --editor <editor>    Editor integration: vscode, goland, vim, none or a custom command (default: vscode)")
+	fmt.Println("  nocode               Skip creation of the editor files and opening the editor (same as --editor none)")
 	fmt.Println("  nomain               Skip creation of the main.go file (only for app)")
+	fmt.Println("  internal             Create an internal/core package for implementation details (only for lib)")
+	fmt.Println("  --kind basic|cli|service|worker")
+	fmt.Println("                      Kind of application: hello world, CLI with subcommands, HTTP service or")
+	fmt.Println("                      long-running worker, each with tests (only for app, default: basic);")
+	fmt.Println("                      a service gets a systemd unit and a Windows service definition in deploy/<name>")
+	fmt.Println("  --docker             Create a Dockerfile, .dockerignore and .devcontainer/devcontainer.json (only for app)")
+	fmt.Println("  --ci github|gitlab|jenkins|none")
+	fmt.Println("                      Create a CI pipeline for analysis, tests, cross-build and releases on tags")
+	fmt.Println("                      (for work: building all modules of the workspace, default: none)")
+	fmt.Println()
+	fmt.Println("Options for work build:")
+	fmt.Println("  --parallel <n>       Maximum number of modules built in parallel (default: number of CPUs)")
+	fmt.Println("  --mode script|go     Run the build script of each module or 'go build ./...' (default: script)")
+	fmt.Println("  quiet                Print the output of failed modules only")
+	fmt.Println()
+	fmt.Println("Options for work skew:")
+	fmt.Println("  --align              Rewrite the go.mod files to require a single version of every dependency")
+	fmt.Println("  --use <module@version,...>")
+	fmt.Println("                      Versions to align to (default: the highest required version or latest tag)")
+	fmt.Println()
+	fmt.Println("Options for foreach:")
+	fmt.Println("  --parallel <n>       Maximum number of modules the command runs in parallel (default: 1)")
+	fmt.Println("  --filter <glob>      Only modules whose folder or module path matches the pattern (e.g. ext/*)")
+	fmt.Println("  -- <command>         The command and its arguments, run in the folder of every module")
+	fmt.Println()
+	fmt.Println("Options for graph:")
+	fmt.Println("  --format dot|mermaid|json")
+	fmt.Println("                      Output format (default: dot)")
+	fmt.Println("  --out <file>         Write the graph to a file (default: stdout)")
+	fmt.Println("  external             Include the direct external dependencies with their versions")
+	fmt.Println()
+	fmt.Println("Options for release:")
+	fmt.Println("  --bump patch|minor|major")
+	fmt.Println("                      Bump of all changed modules (default: derived from conventional commit messages)")
+	fmt.Println("  dryrun               Only show the release plan")
+	fmt.Println("  push                 Push the tags during the release and update go.sum of the dependents")
+	fmt.Println()
+	fmt.Println("Options for apidiff:")
+	fmt.Println("  --from <ref>         Git tag or commit of the old version (default: the last release tag)")
+	fmt.Println("  --version <version>  Version of the new release to check (default: the release tag at HEAD)")
+	fmt.Println()
+	fmt.Println("Options for changelog:")
+	fmt.Println("  --type added|changed|deprecated|removed|fixed|security")
+	fmt.Println("                      Section of the new entry (add only)")
+	fmt.Println("  nogit                Skip the commit and tag of the release (release only)")
+	fmt.Println()
+	fmt.Println("Options for gen tests:")
+	fmt.Println("  nobench              Skip the benchmark stubs")
+	fmt.Println("  nofuzz               Skip the fuzz tests (generated for functions taking []byte or string)")
+	fmt.Println("  dryrun               Print the generated test files instead of writing them")
+	fmt.Println()
+	fmt.Println("Options for coverage:")
+	fmt.Println("  --out <folder>       Folder for the merged profile and the HTML report (default: <path>/coverage)")
+	fmt.Println("  --min <percent>      Minimum coverage of every module (default: \"coverage\" in vasgotools.json)")
+	fmt.Println()
+	fmt.Println("Options for bench:")
+	fmt.Println("  --bench <regexp>     Benchmarks to run (default: .)")
+	fmt.Println("  --count <n>          Number of runs of each benchmark (default: 10)")
+	fmt.Println("  --baseline <ref>     Commit or tag to compare with (default: the latest stored results of another commit)")
+	fmt.Println("  --threshold <percent>")
+	fmt.Println("                      Significant slowdown regarded as regression (default: 5)")
+	fmt.Println("  --history <folder>   Folder of the stored results (default: <path>/.benchmarks)")
+	fmt.Println("  nosave               Do not store the results")
+	fmt.Println()
+	fmt.Println("Options for vendor:")
+	fmt.Println("  verify               Only verify the vendor folder against go.sum (vendor verify)")
+	fmt.Println("  --policy commit|ignore")
+	fmt.Println("                      Commit the vendor folder (.gitattributes) or ignore it (.gitignore, default: commit)")
+	fmt.Println()
+	fmt.Println("Options for mirror:")
+	fmt.Println("  export               Copy the modules listed in go.sum into the mirror and verify it")
+	fmt.Println("  verify               Check that the mirror contains all modules with matching checksums")
+	fmt.Println("  --out <folder>       Folder of the mirror, used as GOPROXY=file://<folder> (default: <path>/mirror)")
+	fmt.Println()
+	fmt.Println("Options for postbuild:")
+	fmt.Println("  --bin <folder>       Folder of the cross-build output in the modules (default: bin)")
+	fmt.Println()
+	fmt.Println("Options for licenses:")
+	fmt.Println("  --format csv|json    Output format of the license report (default: csv)")
+	fmt.Println("  --out <file>         Write the license report to a file (default: stdout)")
+	fmt.Println("  --notices <file>     Third party notices file (default: bin/THIRD_PARTY_NOTICES.txt)")
+	fmt.Println("  nonotices            Skip creation of the third party notices file")
+	fmt.Println()
+	fmt.Println("Options for sbom:")
+	fmt.Println("  --format cyclonedx|spdx")
+	fmt.Println("                      SBOM format (default: cyclonedx)")
+	fmt.Println("  --binary <file>      Read the module information from a built binary instead of the module")
+	fmt.Println("  --out <file>         Write the SBOM to a file (default: stdout)")
+	fmt.Println("  attach               Write the SBOM next to the cross-build output in the bin folder")
+	fmt.Println()
+	fmt.Println("Options for version:")
+	fmt.Println("  --json               Print the version information as JSON")
+	fmt.Println()
+	fmt.Println("Configuration:")
+	fmt.Println("  Settings are read from a vasgotools.json file in the folder or any of its parent folders.")
 	fmt.Println()
 	fmt.Println("Endorsed Folder Structure for Workspaces:")
 	fmt.Println("  The recommended folder structure for a Go workspace is as follows:")
@@ -162,52 +375,80 @@ func printUsage() {
 	fmt.Println("  vasgotools.exe work --path \"C:\\projects\\myworkspace\"")
 	fmt.Println("  vasgotools.exe app myapp --path \"C:\\projects\"")
 	fmt.Println("  vasgotools.exe lib mylib nogit nocode")
+	fmt.Println("  vasgotools.exe lib go-signal-tools internal")
 	fmt.Println("  vasgotools.exe app myapp nomain nogit")
 	fmt.Println("  vasgotools.exe app myapp --module-prefix \"github.com/custom-prefix/\"")
+	fmt.Println("  vasgotools.exe app --editor goland myapp")
+	fmt.Println("  vasgotools.exe app myservice --kind service --docker")
+	fmt.Println("  vasgotools.exe app mytool --kind cli")
+	fmt.Println("  vasgotools.exe work --ci jenkins")
+	fmt.Println("  vasgotools.exe work build --path \"C:\\projects\\myworkspace\" --parallel 4")
+	fmt.Println("  vasgotools.exe work skew --align --use golang.org/x/text@v0.14.0")
+	fmt.Println("  vasgotools.exe release --path \"C:\\projects\\myworkspace\" dryrun")
+	fmt.Println("  vasgotools.exe apidiff --path ext\\mylib --version v1.3.0")
+	fmt.Println("  vasgotools.exe changelog add --type fixed \"crash on empty input\"")
+	fmt.Println("  vasgotools.exe changelog release 1.2.0")
+	fmt.Println("  vasgotools.exe graph --format mermaid --out graph.md external")
+	fmt.Println("  vasgotools.exe foreach --filter \"ext/*\" -- go mod tidy")
+	fmt.Println("  vasgotools.exe app myapp --module-prefix slb --ci github")
+	fmt.Println("  vasgotools.exe add docker --path \"C:\\projects\\myservice\"")
+	fmt.Println("  vasgotools.exe add pkg dsp/filter")
+	fmt.Println("  vasgotools.exe add cmd server --kind service")
+	fmt.Println("  vasgotools.exe gen tests ./dsp/filter")
+	fmt.Println("  vasgotools.exe bench --baseline v1.2.0 --threshold 10")
+	fmt.Println("  vasgotools.exe vendor --path \"C:\\projects\\myworkspace\"")
+	fmt.Println("  vasgotools.exe mirror export --path \"C:\\projects\\myworkspace\" --out D:\\goproxy")
+	fmt.Println("  vasgotools.exe postbuild --path \"C:\\projects\\myservice\"")
+	fmt.Println("  vasgotools.exe coverage --path \"C:\\projects\\myworkspace\" --min 70")
+	fmt.Println("  vasgotools.exe sbom --binary bin\\myapp-windows-amd64.exe --format spdx --out myapp.spdx.json")
+	fmt.Println("  vasgotools.exe licenses --path \"C:\\projects\\myworkspace\" --format json --out licenses.json")
 	fmt.Println()
 	fmt.Println("For more information, use 'go run main.go <command>' to see command-specific options.")
 }
 
+// workCommand dispatches the "work" subcommands. Without a subcommand the workspace is generated.
+func workCommand(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "build":
+			workBuildCommand(args[1:])
+			return
+		case "skew":
+			workSkewCommand(args[1:])
+			return
+		}
+	}
+	generateWorkCommand(args)
+}
+
 func generateWorkCommand(args []string) {
 	// Define a flag set for the "work" command
 	fs := flag.NewFlagSet("work", flag.ExitOnError)
 	folderPath := fs.String("path", "", "Path to the folder (defaults to current working directory)")
-	if err := fs.Parse(args); err != nil {
+	editorName := fs.String("editor", "", "Editor integration: vscode, goland, vim, none or a custom command (default: vscode)")
+	ci := fs.String("ci", ciNone, "CI pipeline building all modules of the workspace: github, gitlab, jenkins or none")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
 		fmt.Println("Error parsing flags:", err)
 		return
 	}
+	if err := validateCI(*ci); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
 
 	// Check for optional flags
-	noGit, noCode := parseOptionalFlags(fs.Args())
+	noGit, noCode := parseOptionalFlags(positional)
 
 	// Use the current working directory if no path is provided
-	err := setDefaultFolderPath(folderPath)
+	err = setDefaultFolderPath(folderPath)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	// Slice to store relative paths of subfolders containing go.mod
-	var goModFolders []string
-
-	// Walk through the directory
-	err = filepath.Walk(*folderPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Check if the current item is a file named "go.mod"
-		if info.Name() == "go.mod" {
-			// Get the relative path of the folder containing go.mod
-			relativePath, err := filepath.Rel(*folderPath, filepath.Dir(path))
-			if err != nil {
-				return err
-			}
-			goModFolders = append(goModFolders, relativePath)
-		}
-		return nil
-	})
-
+	// Collect the relative paths of subfolders containing go.mod
+	goModFolders, err := findGoModFolders(*folderPath)
 	if err != nil {
 		fmt.Println("Error walking the directory:", err)
 		return
@@ -262,23 +503,44 @@ func generateWorkCommand(args []string) {
 		fmt.Println("No subfolders with go.mod found. No go.work file created.")
 	}
 
-	// Create the open_vscode.bat file (if not suppressed)
-	if !noCode {
-		err = createOpenVSCodeFile(*folderPath)
+	// Create the workspace build scripts, unless the workspace root is a module with its own build scripts
+	if slices.Contains(goModFolders, ".") {
+		fmt.Println("Workspace root is a module => creation of workspace build scripts skipped.")
+	} else {
+		err = createWorkspaceBuildScripts(*folderPath)
 		if err != nil {
-			fmt.Println("Error creating open_vscode file:", err)
+			fmt.Println("Error creating workspace build scripts:", err)
 			return
 		}
+		fmt.Println("Workspace build scripts created successfully.")
+	}
 
-		// Execute the open_vscode file
-		err = executeOpenVSCodeFile(*folderPath)
+	// Create the workspace CI pipeline (if requested)
+	if *ci != ciNone {
+		err = createWorkspaceCIFiles(*ci, *folderPath, goModFolders)
 		if err != nil {
-			fmt.Println("Error executing open_vscode file:", err)
+			fmt.Println("Error creating CI pipeline:", err)
 			return
 		}
-		fmt.Println("Visual Studio Code opened successfully.")
+		fmt.Printf("CI pipeline for %s created successfully.\n", *ci)
+	}
+
+	// Create the editor project files and launcher (if not suppressed)
+	ed, err := selectEditor(*editorName, noCode, *folderPath)
+	if err != nil {
+		fmt.Println("Error selecting editor:", err)
+		return
+	}
+	editorTarget := "."
+	if ed != nil {
+		editorTarget, err = ed.setupWorkspace(*folderPath, goModFolders)
+		if err != nil {
+			fmt.Println("Error creating editor files:", err)
+			return
+		}
+		fmt.Printf("Editor files for %s created successfully.\n", ed.name)
 	} else {
-		fmt.Println("Creation and execution of open_vscode.bat skipped.")
+		fmt.Println("Creation of editor files skipped.")
 	}
 
 	// Initialize a Git repository (if not suppressed)
@@ -320,6 +582,11 @@ func generateWorkCommand(args []string) {
 	} else {
 		fmt.Println("Git repository initialization skipped.")
 	}
+
+	// Open the editor as the last step, so a blocking or failing editor does not affect the workspace
+	if ed != nil {
+		ed.launch(*folderPath, editorTarget)
+	}
 }
 
 // addGitSubmodules searches for Git repositories in subfolders and adds them as submodules
@@ -371,10 +638,23 @@ func generateModuleCommand(args []string, isLibrary bool) {
 	fs := flag.NewFlagSet("app", flag.ExitOnError)
 	folderPath := fs.String("path", "", "Path to create the application or library folder (defaults to current working directory)")
 	modulePrefixCmd := fs.String("module-prefix", "none", "Specify the module prefix (default: none, shortcuts: 'vas' for muellerbbm-vas, 'slb' for mbbm-slb)")
-	if err := fs.Parse(args); err != nil {
+	editorName := fs.String("editor", "", "Editor integration: vscode, goland, vim, none or a custom command (default: vscode)")
+	withDocker := fs.Bool("docker", false, "Create a Dockerfile, .dockerignore and .devcontainer/devcontainer.json (only for app)")
+	ci := fs.String("ci", ciNone, "CI pipeline running the analysis, test and cross-build stages: github, gitlab, jenkins or none")
+	kind := fs.String("kind", appKindBasic, "Kind of application: basic, cli, service or worker (only for app)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
 		fmt.Println("Error parsing flags:", err)
 		return
 	}
+	if err := validateCI(*ci); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if err := validateAppKind(*kind); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
 
 	// Determine the module prefix
 	var modulePrefix string
@@ -390,19 +670,19 @@ func generateModuleCommand(args []string, isLibrary bool) {
 	}
 
 	// Ensure the application or library name is provided as the first positional argument
-	if fs.NArg() < 1 {
+	if len(positional) < 1 {
 		fmt.Println("Error: Name is required.")
-		fmt.Println("Usage: vasgotools.exe app <name> [--path <path>] [--module-prefix <prefix>] [nogit] [nocode] [nomain]")
+		fmt.Println("Usage: vasgotools.exe app <name> [--path <path>] [--module-prefix <prefix>] [--editor <editor>] [--kind <kind>] [--docker] [--ci <ci>] [nogit] [nocode] [nomain] [internal]")
 		os.Exit(1)
 	}
-	name := fs.Arg(0)
+	name := positional[0]
 
 	// Check for optional flags
-	noGit, noCode := parseOptionalFlags(fs.Args()[1:])
+	noGit, noCode := parseOptionalFlags(positional[1:])
 	noMain := isLibrary // Automatically skip main.go creation for libraries
 
 	// Use the current working directory if no path is provided
-	err := setDefaultFolderPath(folderPath)
+	err = setDefaultFolderPath(folderPath)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -447,38 +727,81 @@ func generateModuleCommand(args []string, isLibrary bool) {
 	}
 	fmt.Println("LICENSE file created successfully.")
 
-	// Write main.go from the embedded template (if not suppressed)
-	if !noMain {
+	// Create CHANGELOG.md file
+	err = createChangelogFile(folder)
+	if err != nil {
+		fmt.Println("Error creating CHANGELOG.md file:", err)
+		return
+	}
+	fmt.Println("CHANGELOG.md file created successfully.")
 
-		// Create the main.go file from the embedded template
-		mainGoPath := filepath.Join(folder, "main.go")
-		err = os.WriteFile(mainGoPath, []byte(mainGoTemplate), 0o600)
+	// Write the package files of a library or main.go of an app from the embedded templates (if not suppressed)
+	if isLibrary {
+		withInternal := slices.Contains(positional[1:], "internal")
+		packageName, err := createLibraryFiles(folder, fullName, withInternal)
+		if err != nil {
+			fmt.Println("Error creating library files:", err)
+			return
+		}
+		fmt.Printf("Library files of package %s created successfully.\n", packageName)
+	} else if !noMain {
+
+		// Create the main.go file and the version package from the embedded templates
+		err = createMainFiles(folder, fullName, *kind)
 		if err != nil {
 			fmt.Println("Error writing main.go:", err)
 			return
 		}
-		fmt.Println("main.go created successfully.")
+		fmt.Printf("main.go (%s) and internal/version created successfully.\n", *kind)
+
+		// Create the systemd unit and Windows service definition of a service
+		if *kind == appKindService {
+			err = createServiceDeployFiles(folder, path.Base(fullName))
+			if err != nil {
+				fmt.Println("Error creating service files:", err)
+				return
+			}
+			fmt.Printf("Service files in %s created successfully.\n", filepath.Join(deployFolder, path.Base(fullName)))
+		}
 	} else {
 		fmt.Println("Creation of main.go skipped.")
 	}
 
-	// Create the open_vscode.bat file (if not suppressed)
-	if !noCode {
-		err = createOpenVSCodeFile(folder)
+	// Create the CI pipeline (if requested)
+	if *ci != ciNone {
+		err = createCIFiles(*ci, folder)
 		if err != nil {
-			fmt.Println("Error creating open_vscode file:", err)
+			fmt.Println("Error creating CI pipeline:", err)
 			return
 		}
+		fmt.Printf("CI pipeline for %s created successfully.\n", *ci)
+	}
 
-		// Execute the open_vscode file
-		err = executeOpenVSCodeFile(folder)
+	// Create the Docker files (if requested)
+	if *withDocker && !isLibrary {
+		err = createDockerFiles(folder)
 		if err != nil {
-			fmt.Println("Error executing open_vscode file:", err)
+			fmt.Println("Error creating Docker files:", err)
 			return
 		}
-		fmt.Println("Visual Studio Code opened successfully.")
+		fmt.Println("Dockerfile, .dockerignore and .devcontainer/devcontainer.json created successfully.")
+	}
+
+	// Create the editor project files and launcher (if not suppressed)
+	ed, err := selectEditor(*editorName, noCode, *folderPath)
+	if err != nil {
+		fmt.Println("Error selecting editor:", err)
+		return
+	}
+	if ed != nil {
+		err = ed.setupModule(folder, name, isLibrary)
+		if err != nil {
+			fmt.Println("Error creating editor files:", err)
+			return
+		}
+		fmt.Printf("Editor files for %s created successfully.\n", ed.name)
 	} else {
-		fmt.Println("Creation and execution of open_vscode.bat skipped.")
+		fmt.Println("Creation of editor files skipped.")
 	}
 
 	// Initialize a Git repository (if not suppressed)
@@ -523,6 +846,35 @@ func generateModuleCommand(args []string, isLibrary bool) {
 	}
 
 	fmt.Printf("'%s' created successfully in folder '%s'.\n", fullName, folder)
+
+	// Open the editor as the last step, so a blocking or failing editor does not affect the module
+	if ed != nil {
+		ed.launch(folder, ".")
+	}
+}
+
+// parseFlags parses args with fs and returns the positional arguments. In contrast to fs.Parse, flags may
+// also follow positional arguments (e.g. "app myapp --path <path>"). Arguments after "--" are never
+// interpreted as flags.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		remaining := fs.Args()
+		if len(remaining) == 0 {
+			return positional, nil
+		}
+
+		// fs.Parse stops after a "--" terminator: everything that follows is positional
+		if consumed := len(args) - len(remaining); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, remaining...), nil
+		}
+
+		positional = append(positional, remaining[0])
+		args = remaining[1:]
+	}
 }
 
 // parseOptionalFlags parses the optional "nogit" and "nocode" flags from the arguments.
@@ -573,6 +925,10 @@ func createGitAttributesFile(folderPath string) error {
 # shell script files: always LF
 *.sh text eol=lf
 
+# systemd units and environment files: always LF
+*.service text eol=lf
+*.env     text eol=lf
+
 # JSON, Markdown, text files: always LF
 *.json text eol=lf
 *.md   text eol=lf
@@ -582,6 +938,7 @@ func createGitAttributesFile(folderPath string) error {
 
 # Windows batch files: always CRLF
 *.bat text eol=crlf
+*.ps1 text eol=crlf
 
 # Binary files: no line ending conversion
 *.png  binary
@@ -595,60 +952,6 @@ func createGitAttributesFile(folderPath string) error {
 	return os.WriteFile(gitattributesPath, []byte(gitattributesContent), 0o644)
 }
 
-func createOpenVSCodeBatchFile(folderPath string) error {
-	batchFilePath := filepath.Join(folderPath, openVSCodeBatchFile)
-	batchFileContent := "code . | exit 0\n"
-	return os.WriteFile(batchFilePath, []byte(batchFileContent), 0o600)
-}
-
-func executeOpenVSCodeBatchFile(folderPath string) error {
-	batchFilePath := filepath.Join(folderPath, openVSCodeBatchFile)
-	//nolint:gosec // G204: Safe usage - batchFilePath is controlled by the application
-	cmd := exec.Command("cmd", "/C", batchFilePath)
-	cmd.Dir = folderPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	fmt.Println("Opening Visual Studio Code...")
-	return cmd.Run()
-}
-
-func createOpenVSCodeShellScript(folderPath string) error {
-	scriptFilePath := filepath.Join(folderPath, openVSCodeShellFile)
-	scriptContent := "#!/bin/bash\ncode . || exit 0\n"
-	//nolint:gosec // G306: Script needs to be executable
-	err := os.WriteFile(scriptFilePath, []byte(scriptContent), 0o700) // Make the script executable
-	if err != nil {
-		return fmt.Errorf("error creating open_vscode.sh: %w", err)
-	}
-	return nil
-}
-
-func executeOpenVSCodeShellScript(folderPath string) error {
-	scriptFilePath := filepath.Join(folderPath, openVSCodeShellFile)
-	//nolint:gosec // G204: Safe usage - scriptFilePath is controlled by the application
-	cmd := exec.Command("bash", scriptFilePath)
-	cmd.Dir = folderPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	fmt.Println("Opening Visual Studio Code...")
-	return cmd.Run()
-}
-
-func createOpenVSCodeFile(folderPath string) error {
-	err1 := createOpenVSCodeBatchFile(folderPath)
-	err2 := createOpenVSCodeShellScript(folderPath)
-	return errors.Join(err1, err2)
-}
-
-func executeOpenVSCodeFile(folderPath string) error {
-	if runtime.GOOS == "windows" {
-		return executeOpenVSCodeBatchFile(folderPath)
-	}
-	return executeOpenVSCodeShellScript(folderPath)
-}
-
 func createBuildBatchFile(folderPath string) error {
 	batchFilePath := filepath.Join(folderPath, "build.bat")
 	err := os.WriteFile(batchFilePath, []byte(buildBatTemplate), 0o600)
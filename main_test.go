@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantPositional []string
+		wantPath       string
+		wantForce      bool
+	}{
+		{
+			name:           "flags before positionals",
+			args:           []string{"--path", "/src", "--force", "myapp", "nogit"},
+			wantPositional: []string{"myapp", "nogit"},
+			wantPath:       "/src",
+			wantForce:      true,
+		},
+		{
+			name:           "flags after positionals",
+			args:           []string{"myapp", "--path", "/src", "nogit", "--force"},
+			wantPositional: []string{"myapp", "nogit"},
+			wantPath:       "/src",
+			wantForce:      true,
+		},
+		{
+			name:           "flag with equals sign",
+			args:           []string{"myapp", "--path=/src", "-force=false"},
+			wantPositional: []string{"myapp"},
+			wantPath:       "/src",
+		},
+		{
+			name:           "arguments after terminator are positional",
+			args:           []string{"myapp", "--", "--path", "/src", "--force"},
+			wantPositional: []string{"myapp", "--path", "/src", "--force"},
+		},
+		{
+			name:           "flags before terminator are parsed",
+			args:           []string{"--force", "--", "-x"},
+			wantPositional: []string{"-x"},
+			wantForce:      true,
+		},
+		{
+			name: "no arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			path := fs.String("path", "", "")
+			force := fs.Bool("force", false, "")
+
+			positional, err := parseFlags(fs, tt.args)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(positional, tt.wantPositional) {
+				t.Errorf("parseFlags() positional = %q, want %q", positional, tt.wantPositional)
+			}
+			if *path != tt.wantPath {
+				t.Errorf("parseFlags() path = %q, want %q", *path, tt.wantPath)
+			}
+			if *force != tt.wantForce {
+				t.Errorf("parseFlags() force = %v, want %v", *force, tt.wantForce)
+			}
+		})
+	}
+}
+
+func TestParseFlagsUnknownFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("path", "", "")
+
+	if _, err := parseFlags(fs, []string{"myapp", "--unknown"}); err == nil {
+		t.Error("parseFlags() error = nil, want an error for an unknown flag")
+	}
+}
@@ -0,0 +1,546 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// mirrorChecksumFile is the file of the mirror holding the checksums of all exported modules in
+// go.sum format, so the mirror can be verified without access to the checksum database.
+const mirrorChecksumFile = "mirror.sum"
+
+// mirrorModule is a module version required by a module or workspace with its checksums from
+// go.sum. An empty zipSum means only the go.mod file is needed (modules of the module graph
+// that are not built).
+type mirrorModule struct {
+	path    string
+	version string
+	modSum  string
+	zipSum  string
+}
+
+func (module *mirrorModule) String() string {
+	return module.path + "@" + module.version
+}
+
+func printMirrorUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  vasgotools.exe mirror export [--path <module or workspace>] [--out <folder>]")
+	fmt.Println("  vasgotools.exe mirror verify [--path <module or workspace>] [--out <folder>]")
+}
+
+// mirrorCommand exports the module versions required by a module or workspace from the module
+// cache into a folder usable as GOPROXY=file://... and verifies such a mirror.
+func mirrorCommand(args []string) {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "verify") {
+		printMirrorUsage()
+		os.Exit(1)
+	}
+	action := args[0]
+
+	// Define a flag set for the "mirror" command
+	fs := flag.NewFlagSet("mirror", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module or workspace (defaults to current working directory)")
+	outPath := fs.String("out", "", "Folder of the module mirror (default: <path>/mirror)")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+	if len(positional) > 0 {
+		fmt.Printf("Error: unexpected argument '%s'\n", positional[0])
+		printMirrorUsage()
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if *outPath == "" {
+		*outPath = filepath.Join(*folderPath, "mirror")
+	}
+	*outPath, err = filepath.Abs(*outPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	modules, err := requiredModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	if action == "export" {
+		err = exportMirror(*folderPath, *outPath, modules)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d module versions exported to %s\n", len(modules), *outPath)
+	}
+
+	problems, err := verifyMirror(*outPath, modules)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(problems) > 0 {
+		fmt.Println("The module mirror is incomplete or corrupted:")
+		for _, problem := range problems {
+			fmt.Println("  " + problem)
+		}
+		fmt.Println("Run 'vasgotools mirror export' on a machine with the modules in its module cache.")
+		os.Exit(1)
+	}
+	fmt.Printf("Module mirror verified successfully (%d module versions).\n", len(modules))
+	fmt.Println("Use it with:")
+	fmt.Printf("  GOPROXY=%s GOSUMDB=off\n", fileURL(*outPath))
+}
+
+// requiredModules returns the module versions listed in the go.sum files of the module or
+// workspace in folderPath (and go.work.sum), sorted by path and version. These are all module
+// versions the go command needs to build and test the modules.
+func requiredModules(folderPath string) ([]*mirrorModule, error) {
+	sumFiles := []string{filepath.Join(folderPath, "go.sum")}
+	if isWorkspace(folderPath) {
+		folders, err := workspaceModuleFolders(folderPath)
+		if err != nil {
+			return nil, err
+		}
+		sumFiles = []string{filepath.Join(folderPath, "go.work.sum")}
+		for _, folder := range folders {
+			sumFiles = append(sumFiles, filepath.Join(folderPath, folder, "go.sum"))
+		}
+	}
+
+	modules := make(map[string]*mirrorModule)
+	for _, sumFile := range sumFiles {
+		err := readSumFile(sumFile, modules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sortedMirrorModules(modules), nil
+}
+
+// readSumFile adds the entries of the go.sum file sumFile (if it exists) to modules.
+func readSumFile(sumFile string, modules map[string]*mirrorModule) error {
+	//nolint:gosec // G304: Safe usage - sumFile is a go.sum file of the module or workspace
+	file, err := os.Open(sumFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return fmt.Errorf("%s:%d: invalid line", sumFile, line)
+		}
+
+		version, isGoMod := strings.CutSuffix(fields[1], "/go.mod")
+		key := fields[0] + "@" + version
+		module := modules[key]
+		if module == nil {
+			module = &mirrorModule{path: fields[0], version: version}
+			modules[key] = module
+		}
+		sum := &module.zipSum
+		if isGoMod {
+			sum = &module.modSum
+		}
+		if *sum != "" && *sum != fields[2] {
+			return fmt.Errorf("%s:%d: conflicting checksums for %s", sumFile, line, fields[1])
+		}
+		*sum = fields[2]
+	}
+	return scanner.Err()
+}
+
+// sortedMirrorModules returns the modules sorted by path and version.
+func sortedMirrorModules(modules map[string]*mirrorModule) []*mirrorModule {
+	sorted := make([]*mirrorModule, 0, len(modules))
+	for _, module := range modules {
+		sorted = append(sorted, module)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].path != sorted[j].path {
+			return sorted[i].path < sorted[j].path
+		}
+		return compareSemver(sorted[i].version, sorted[j].version) < 0
+	})
+	return sorted
+}
+
+// exportMirror copies the files of the modules from the download cache of the module cache into
+// outPath using the layout of the module proxy protocol (<module>/@v/list, <version>.info,
+// .mod and .zip) and records their checksums in mirror.sum. Modules missing in the module cache
+// are downloaded with "go mod download" first.
+func exportMirror(folderPath, outPath string, modules []*mirrorModule) error {
+	cachePath, err := moduleDownloadCache()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(outPath, 0o750)
+	if err != nil {
+		return err
+	}
+	checksums := make(map[string]*mirrorModule)
+	err = readSumFile(filepath.Join(outPath, mirrorChecksumFile), checksums)
+	if err != nil {
+		return err
+	}
+
+	versions := make(map[string][]string)
+	for _, module := range modules {
+		sourcePath, err := escapedModuleFolder(cachePath, module)
+		if err != nil {
+			return err
+		}
+		version, err := escapeModulePath(module.version)
+		if err != nil {
+			return err
+		}
+		if !fileExists(filepath.Join(sourcePath, version+".mod")) ||
+			(module.zipSum != "" && !fileExists(filepath.Join(sourcePath, version+".zip"))) {
+			err = downloadModule(folderPath, module)
+			if err != nil {
+				return err
+			}
+		}
+
+		targetPath, err := escapedModuleFolder(outPath, module)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(targetPath, 0o750)
+		if err != nil {
+			return err
+		}
+
+		exported := &mirrorModule{path: module.path, version: module.version}
+		err = copyFile(filepath.Join(sourcePath, version+".mod"), filepath.Join(targetPath, version+".mod"))
+		if err != nil {
+			return err
+		}
+		exported.modSum, err = hashGoMod(filepath.Join(targetPath, version+".mod"))
+		if err != nil {
+			return err
+		}
+		if module.zipSum != "" {
+			err = copyFile(filepath.Join(sourcePath, version+".zip"), filepath.Join(targetPath, version+".zip"))
+			if err != nil {
+				return err
+			}
+			exported.zipSum, err = hashZip(filepath.Join(targetPath, version+".zip"))
+			if err != nil {
+				return err
+			}
+		}
+		err = writeModuleInfo(filepath.Join(sourcePath, version+".info"), filepath.Join(targetPath, version+".info"), module.version)
+		if err != nil {
+			return err
+		}
+
+		// Keep the zip checksum of a version exported before by another module or workspace
+		if previous := checksums[module.String()]; previous != nil && exported.zipSum == "" {
+			exported.zipSum = previous.zipSum
+		}
+		checksums[module.String()] = exported
+		versions[targetPath] = append(versions[targetPath], module.version)
+	}
+
+	for targetPath, moduleVersions := range versions {
+		err = writeVersionList(filepath.Join(targetPath, "list"), moduleVersions)
+		if err != nil {
+			return err
+		}
+	}
+	return writeMirrorChecksums(filepath.Join(outPath, mirrorChecksumFile), sortedMirrorModules(checksums))
+}
+
+// verifyMirror checks that the mirror in outPath contains the files of all modules and that
+// their checksums match go.sum and mirror.sum. The problems found are returned.
+func verifyMirror(outPath string, modules []*mirrorModule) ([]string, error) {
+	sumPath := filepath.Join(outPath, mirrorChecksumFile)
+	if !fileExists(sumPath) {
+		return nil, fmt.Errorf("no module mirror in %s (run 'vasgotools mirror export' first)", outPath)
+	}
+	checksums := make(map[string]*mirrorModule)
+	err := readSumFile(sumPath, checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, module := range modules {
+		targetPath, err := escapedModuleFolder(outPath, module)
+		if err != nil {
+			return nil, err
+		}
+		version, err := escapeModulePath(module.version)
+		if err != nil {
+			return nil, err
+		}
+
+		recorded := checksums[module.String()]
+		if recorded == nil {
+			problems = append(problems, fmt.Sprintf("missing: %s (not in %s)", module, mirrorChecksumFile))
+			continue
+		}
+		if !fileExists(filepath.Join(targetPath, version+".info")) {
+			problems = append(problems, fmt.Sprintf("missing: %s (.info)", module))
+		}
+		listed, err := readVersionList(filepath.Join(targetPath, "list"))
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(listed, module.version) {
+			problems = append(problems, fmt.Sprintf("missing: %s (not in @v/list)", module))
+		}
+
+		problems = append(problems, checkMirrorFile(module, filepath.Join(targetPath, version+".mod"), ".mod",
+			hashGoMod, module.modSum, recorded.modSum)...)
+		if module.zipSum != "" {
+			problems = append(problems, checkMirrorFile(module, filepath.Join(targetPath, version+".zip"), ".zip",
+				hashZip, module.zipSum, recorded.zipSum)...)
+		}
+	}
+	return problems, nil
+}
+
+// checkMirrorFile compares the checksum of a file of the mirror with the checksums expected by
+// go.sum (goSum) and recorded in mirror.sum (mirrorSum); empty checksums are not checked.
+func checkMirrorFile(module *mirrorModule, filePath, kind string, hashFile func(string) (string, error), goSum, mirrorSum string) []string {
+	if !fileExists(filePath) {
+		return []string{fmt.Sprintf("missing: %s (%s)", module, kind)}
+	}
+	sum, err := hashFile(filePath)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid: %s (%s): %v", module, kind, err)}
+	}
+
+	var problems []string
+	if goSum != "" && sum != goSum {
+		problems = append(problems, fmt.Sprintf("checksum mismatch: %s (%s) %s, go.sum: %s", module, kind, sum, goSum))
+	}
+	if mirrorSum == "" {
+		problems = append(problems, fmt.Sprintf("missing: %s (%s checksum in %s)", module, kind, mirrorChecksumFile))
+	} else if sum != mirrorSum {
+		problems = append(problems, fmt.Sprintf("checksum mismatch: %s (%s) %s, %s: %s", module, kind, sum, mirrorChecksumFile, mirrorSum))
+	}
+	return problems
+}
+
+// moduleDownloadCache returns the download cache of the module cache ($GOMODCACHE/cache/download),
+// which has the layout of a module proxy.
+func moduleDownloadCache() (string, error) {
+	output, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return "", fmt.Errorf("error reading GOMODCACHE: %w", err)
+	}
+	return filepath.Join(strings.TrimSpace(string(output)), "cache", "download"), nil
+}
+
+// downloadModule adds a module version to the module cache with "go mod download".
+func downloadModule(folderPath string, module *mirrorModule) error {
+	fmt.Printf("Downloading %s\n", module)
+	//nolint:gosec // G204: Safe usage - the module version is read from go.sum
+	cmd := exec.Command("go", "mod", "download", "-json", module.String())
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		var result struct{ Error string }
+		if json.Unmarshal(output, &result) == nil && result.Error != "" {
+			return fmt.Errorf("error downloading %s: %s", module, result.Error)
+		}
+		return fmt.Errorf("error downloading %s: %w", module, err)
+	}
+	return nil
+}
+
+// escapedModuleFolder returns the @v folder of the module below rootPath.
+func escapedModuleFolder(rootPath string, module *mirrorModule) (string, error) {
+	escapedPath, err := escapeModulePath(module.path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(rootPath, filepath.FromSlash(escapedPath), "@v"), nil
+}
+
+// escapeModulePath escapes a module path or version for the module cache and proxy: upper case
+// letters are replaced by "!" followed by the lower case letter.
+func escapeModulePath(path string) (string, error) {
+	var escaped strings.Builder
+	for _, r := range path {
+		switch {
+		case r == '!' || r > unicode.MaxASCII:
+			return "", fmt.Errorf("invalid character in module path or version %q", path)
+		case unicode.IsUpper(r):
+			escaped.WriteByte('!')
+			escaped.WriteRune(unicode.ToLower(r))
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String(), nil
+}
+
+// copyFile copies the file sourcePath to targetPath.
+func copyFile(sourcePath, targetPath string) error {
+	//nolint:gosec // G304: Safe usage - sourcePath is controlled by the application
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	//nolint:gosec // G304: Safe usage - targetPath is controlled by the application
+	target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(target, source)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeModuleInfo copies the .info file of a module version, or writes a minimal one if the
+// module cache only has its go.mod file.
+func writeModuleInfo(sourcePath, targetPath, version string) error {
+	if fileExists(sourcePath) {
+		return copyFile(sourcePath, targetPath)
+	}
+	info, err := json.Marshal(struct{ Version string }{version})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(targetPath, info, 0o600)
+}
+
+// readVersionList reads the @v/list file of a module (empty if it does not exist).
+func readVersionList(listPath string) ([]string, error) {
+	//nolint:gosec // G304: Safe usage - the file is in the mirror folder
+	content, err := os.ReadFile(listPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(content)), nil
+}
+
+// writeVersionList adds versions to the @v/list file of a module, sorted by semver precedence.
+func writeVersionList(listPath string, versions []string) error {
+	listed, err := readVersionList(listPath)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if !slices.Contains(listed, version) {
+			listed = append(listed, version)
+		}
+	}
+	slices.SortFunc(listed, compareSemver)
+	return os.WriteFile(listPath, []byte(strings.Join(listed, "\n")+"\n"), 0o600)
+}
+
+// writeMirrorChecksums writes the checksums of the modules in go.sum format.
+func writeMirrorChecksums(sumPath string, modules []*mirrorModule) error {
+	var content strings.Builder
+	for _, module := range modules {
+		if module.zipSum != "" {
+			fmt.Fprintf(&content, "%s %s %s\n", module.path, module.version, module.zipSum)
+		}
+		fmt.Fprintf(&content, "%s %s/go.mod %s\n", module.path, module.version, module.modSum)
+	}
+	return os.WriteFile(sumPath, []byte(content.String()), 0o600)
+}
+
+// hashGoMod returns the go.sum checksum ("h1:" hash) of a go.mod file.
+func hashGoMod(modPath string) (string, error) {
+	//nolint:gosec // G304: Safe usage - the file is in the mirror folder
+	content, err := os.ReadFile(modPath)
+	if err != nil {
+		return "", err
+	}
+	summary := sha256.New()
+	fmt.Fprintf(summary, "%x  %s\n", sha256.Sum256(content), "go.mod")
+	return hashSummary(summary), nil
+}
+
+// hashZip returns the go.sum checksum ("h1:" hash) of a module zip file: the SHA-256 of a summary
+// listing the SHA-256 and name of every file, sorted by name.
+func hashZip(zipPath string) (string, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+
+	files := slices.Clone(reader.File)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	summary := sha256.New()
+	for _, file := range files {
+		if strings.Contains(file.Name, "\n") {
+			return "", fmt.Errorf("invalid file name %q in %s", file.Name, zipPath)
+		}
+		content, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.New()
+		//nolint:gosec // G110: Safe usage - the zip file is a module from the module cache
+		_, err = io.Copy(fileHash, content)
+		_ = content.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", fileHash.Sum(nil), file.Name)
+	}
+	return hashSummary(summary), nil
+}
+
+// hashSummary returns the "h1:" checksum of a summary hash.
+func hashSummary(summary hash.Hash) string {
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil))
+}
+
+// fileURL returns the file:// URL of an absolute path (also for Windows paths like C:\mirror).
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return "file://" + path
+}
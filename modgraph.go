@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// workspaceModule is a module of a workspace together with its requirements.
+type workspaceModule struct {
+	// Folder is the folder of the module relative to the workspace root
+	Folder string
+	// Path is the module path from go.mod
+	Path string
+	// Mod is the parsed go.mod file
+	Mod *goModFile
+	// Deps lists the module paths of the workspace modules this module requires
+	Deps []string
+}
+
+// loadWorkspaceModules reads the go.mod files of all modules of the workspace in rootPath and
+// determines the dependencies between them. Two folders declaring the same module path are an error.
+func loadWorkspaceModules(rootPath string) ([]*workspaceModule, error) {
+	folders, err := workspaceModuleFolders(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]*workspaceModule, 0, len(folders))
+	folderByPath := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		mod, err := readGoMod(filepath.Join(rootPath, folder))
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := folderByPath[mod.Module.Path]; ok {
+			return nil, fmt.Errorf("module %s is declared in %s and %s", mod.Module.Path, other, folder)
+		}
+		folderByPath[mod.Module.Path] = folder
+		modules = append(modules, &workspaceModule{Folder: folder, Path: mod.Module.Path, Mod: mod})
+	}
+
+	for _, module := range modules {
+		for _, require := range module.Mod.Require {
+			if _, ok := folderByPath[require.Path]; ok && require.Path != module.Path {
+				module.Deps = append(module.Deps, require.Path)
+			}
+		}
+		slices.Sort(module.Deps)
+	}
+	return modules, nil
+}
+
+// findModuleCycles returns the dependency cycles between the workspace modules, each as a list of
+// module paths where the first module is repeated at the end.
+func findModuleCycles(modules []*workspaceModule) [][]string {
+	byPath := modulesByPath(modules)
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(modules))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(path string)
+	visit = func(path string) {
+		state[path] = inProgress
+		stack = append(stack, path)
+		for _, dep := range byPath[path].Deps {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case inProgress:
+				start := slices.Index(stack, dep)
+				cycle := slices.Clone(stack[start:])
+				cycles = append(cycles, append(cycle, dep))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[path] = done
+	}
+
+	for _, module := range modules {
+		if state[module.Path] == unvisited {
+			visit(module.Path)
+		}
+	}
+	return cycles
+}
+
+// sortModulesByDependencies returns the modules in dependency order: every module comes after the
+// modules it requires. An error is returned if the dependencies contain a cycle.
+func sortModulesByDependencies(modules []*workspaceModule) ([]*workspaceModule, error) {
+	if cycles := findModuleCycles(modules); len(cycles) > 0 {
+		return nil, fmt.Errorf("dependency cycle between workspace modules: %s", strings.Join(cycles[0], " -> "))
+	}
+
+	byPath := modulesByPath(modules)
+	sorted := make([]*workspaceModule, 0, len(modules))
+	added := make(map[string]bool, len(modules))
+
+	var add func(module *workspaceModule)
+	add = func(module *workspaceModule) {
+		if added[module.Path] {
+			return
+		}
+		added[module.Path] = true
+		for _, dep := range module.Deps {
+			add(byPath[dep])
+		}
+		sorted = append(sorted, module)
+	}
+
+	for _, module := range modules {
+		add(module)
+	}
+	return sorted, nil
+}
+
+// modulesByPath indexes the workspace modules by their module path.
+func modulesByPath(modules []*workspaceModule) map[string]*workspaceModule {
+	byPath := make(map[string]*workspaceModule, len(modules))
+	for _, module := range modules {
+		byPath[module.Path] = module
+	}
+	return byPath
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// deployFolder is the folder of a module holding the deployment files of its services, one sub
+// folder per command.
+const deployFolder = "deploy"
+
+// serviceDeployFile is a deployment file of a service: its name (with placeholders), template,
+// permissions and the target operating system it belongs to.
+type serviceDeployFile struct {
+	name     string
+	template string
+	perm     os.FileMode
+	goos     string
+}
+
+// serviceDeployFiles returns the deployment files of a service: a systemd unit with environment
+// file and install/uninstall scripts for Linux, a WinSW service definition with PowerShell
+// install/uninstall scripts for Windows.
+func serviceDeployFiles() []serviceDeployFile {
+	return []serviceDeployFile{
+		{"{{APP_NAME}}.service", systemdUnitTemplate, 0o600, "linux"},
+		{"{{APP_NAME}}.env", serviceEnvTemplate, 0o600, "linux"},
+		{"install.sh", serviceInstallShTemplate, 0o700, "linux"},
+		{"uninstall.sh", serviceUninstallShTemplate, 0o700, "linux"},
+		{"{{APP_NAME}}-service.xml", serviceWinSWXMLTemplate, 0o600, "windows"},
+		{"install.ps1", serviceInstallPs1Template, 0o600, "windows"},
+		{"uninstall.ps1", serviceUninstallPs1Template, 0o600, "windows"},
+	}
+}
+
+// serviceTemplateValues returns the placeholder values of the deployment files of the command:
+// the service is installed in /opt/<command> on Linux and C:\Program Files\<command> on Windows.
+func serviceTemplateValues(command string) map[string]string {
+	windowsInstallDir := `C:\Program Files\` + command
+	return map[string]string{
+		"APP_NAME":            command,
+		"INSTALL_DIR":         "/opt/" + command,
+		"BINARY_PATH":         "/opt/" + command + "/" + command,
+		"WINDOWS_INSTALL_DIR": windowsInstallDir,
+		"WINDOWS_BINARY_PATH": windowsInstallDir + `\` + command + ".exe",
+	}
+}
+
+// createServiceDeployFiles creates the deployment files of the service command in
+// <folderPath>/deploy/<command>. command is the name of the binary built by the cross-build scripts.
+func createServiceDeployFiles(folderPath, command string) error {
+	commandPath := filepath.Join(folderPath, deployFolder, command)
+	err := os.MkdirAll(commandPath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating %s folder: %w", filepath.Join(deployFolder, command), err)
+	}
+
+	values := serviceTemplateValues(command)
+	for _, file := range serviceDeployFiles() {
+		name := renderTemplate(file.name, values)
+		err = os.WriteFile(filepath.Join(commandPath, name), []byte(renderTemplate(file.template, values)), file.perm)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %w", filepath.Join(deployFolder, command, name), err)
+		}
+	}
+	return nil
+}
+
+// postbuildCommand lays the deployment files of the services next to the cross-build output:
+// for every Linux and Windows binary of a service a folder bin/<command>-<os>-<arch>-service is
+// created with the binary and the deployment files of that operating system.
+func postbuildCommand(args []string) {
+	// Define a flag set for the "postbuild" command
+	fs := flag.NewFlagSet("postbuild", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module or workspace (defaults to current working directory)")
+	binFolder := fs.String("bin", "bin", "Folder of the cross-build output relative to the module")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	folders := []string{"."}
+	if isWorkspace(*folderPath) {
+		folders, err = workspaceModuleFolders(*folderPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	packages := 0
+	for _, folder := range folders {
+		created, err := packageServices(filepath.Join(*folderPath, folder), *binFolder)
+		if err != nil {
+			fmt.Printf("Error in %s: %v\n", folder, err)
+			os.Exit(1)
+		}
+		packages += created
+	}
+	if packages == 0 {
+		fmt.Println("No service packages created (services need deploy/<command> and the cross-build output).")
+		return
+	}
+	fmt.Printf("%d service package(s) created.\n", packages)
+}
+
+// packageServices creates the service packages of the module in modulePath and returns their number.
+func packageServices(modulePath, binFolder string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(modulePath, deployFolder))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	binPath := filepath.Join(modulePath, binFolder)
+	binaries, err := os.ReadDir(binPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	packages := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		command := entry.Name()
+		found := false
+		for _, binary := range binaries {
+			goos, arch, ok := parseBinaryName(binary.Name(), command)
+			if !ok || binary.IsDir() {
+				continue
+			}
+			found = true
+			if goos != "linux" && goos != "windows" {
+				fmt.Printf("%s: no service files for %s, skipped\n", binary.Name(), goos)
+				continue
+			}
+
+			packagePath := filepath.Join(binPath, command+"-"+goos+"-"+arch+"-service")
+			err = createServicePackage(packagePath, filepath.Join(binPath, binary.Name()),
+				filepath.Join(modulePath, deployFolder, command), command, goos)
+			if err != nil {
+				return packages, err
+			}
+			fmt.Printf("%s created.\n", packagePath)
+			packages++
+		}
+		if !found {
+			fmt.Printf("No binaries of %s in %s (run the cross-build script first)\n", command, binPath)
+		}
+	}
+	return packages, nil
+}
+
+// binaryArch matches the architecture of a binary name with an optional variant, e.g. "amd64" or "arm-v7".
+var binaryArch = regexp.MustCompile(`^[a-z0-9]+(-v[0-9]+)?$`)
+
+// parseBinaryName returns the operating system and architecture of a cross-build binary named
+// <command>-<os>-<arch>[.exe], the architecture may include a variant (e.g. linux-arm-v7).
+func parseBinaryName(name, command string) (goos, arch string, ok bool) {
+	target, ok := strings.CutPrefix(strings.TrimSuffix(name, ".exe"), command+"-")
+	if !ok {
+		return "", "", false
+	}
+	goos, arch, ok = strings.Cut(target, "-")
+	if !ok || goos == "" || !binaryArch.MatchString(arch) {
+		return "", "", false
+	}
+	return goos, arch, true
+}
+
+// createServicePackage (re)creates the folder packagePath with the binary (named like the
+// command) and the deployment files of the operating system goos from deployPath.
+func createServicePackage(packagePath, binaryPath, deployPath, command, goos string) error {
+	err := os.RemoveAll(packagePath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(packagePath, 0o750)
+	if err != nil {
+		return err
+	}
+
+	binaryName := command
+	if goos == "windows" {
+		binaryName += ".exe"
+	}
+	err = copyFile(binaryPath, filepath.Join(packagePath, binaryName))
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // G302: Safe usage - the binary has to be executable
+	err = os.Chmod(filepath.Join(packagePath, binaryName), 0o700)
+	if err != nil {
+		return err
+	}
+
+	values := serviceTemplateValues(command)
+	for _, file := range serviceDeployFiles() {
+		if file.goos != goos {
+			continue
+		}
+		name := renderTemplate(file.name, values)
+		err = copyFile(filepath.Join(deployPath, name), filepath.Join(packagePath, name))
+		if err != nil {
+			return fmt.Errorf("error copying %s (deleted from %s?): %w", name, deployPath, err)
+		}
+		err = os.Chmod(filepath.Join(packagePath, name), file.perm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestParseBinaryName(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		wantOS   string
+		wantArch string
+		wantOK   bool
+	}{
+		{"server-linux-amd64", "server", "linux", "amd64", true},
+		{"server-windows-amd64.exe", "server", "windows", "amd64", true},
+		{"server-linux-arm-v7", "server", "linux", "arm-v7", true},
+		{"server-darwin-arm64", "server", "darwin", "arm64", true},
+		{"my-server-linux-arm64", "my-server", "linux", "arm64", true},
+		{"server-linux-amd64-service", "server", "", "", false},
+		{"server-linux", "server", "", "", false},
+		{"server-linux-", "server", "", "", false},
+		{"server", "server", "", "", false},
+		{"other-linux-amd64", "server", "", "", false},
+		{"server.exe", "server", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goos, arch, ok := parseBinaryName(tt.name, tt.command)
+			if ok != tt.wantOK || goos != tt.wantOS || arch != tt.wantArch {
+				t.Errorf("parseBinaryName() = %q, %q, %v, want %q, %q, %v", goos, arch, ok, tt.wantOS, tt.wantArch, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	bumpNone  = ""
+	bumpPatch = "patch"
+	bumpMinor = "minor"
+	bumpMajor = "major"
+)
+
+// bumpOrder ranks the bump levels, a higher rank wins.
+var bumpOrder = []string{bumpNone, bumpPatch, bumpMinor, bumpMajor}
+
+// releaseRemote is the Git remote the release tags are pushed to.
+const releaseRemote = "origin"
+
+// Conventional commit subjects: "feat: ..." is a feature, "fix!: ..." or "feat(api)!: ..." a breaking change
+var (
+	featureCommit  = regexp.MustCompile(`^feat(\([^)]*\))?!?:`)
+	breakingCommit = regexp.MustCompile(`^[a-z]+(\([^)]*\))?!:`)
+)
+
+// releaseStep is the release of a single workspace module.
+type releaseStep struct {
+	module *workspaceModule
+	// tagPrefix is the folder of the module relative to the Git repository root (see moduleTagPrefix)
+	tagPrefix string
+	current   string
+	next      string
+	reason    string
+}
+
+// tag returns the Git tag of the release.
+func (step *releaseStep) tag() string {
+	return step.tagPrefix + step.next
+}
+
+func releaseCommand(args []string) {
+	// Define a flag set for the "release" command
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace (defaults to current working directory)")
+	bump := fs.String("bump", "", "Bump of all changed modules: patch, minor or major (default: derived from the commit messages)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	dryRun := slices.Contains(positional, "dryrun")
+	push := slices.Contains(positional, "push")
+
+	if *bump != bumpNone && !slices.Contains(bumpOrder, *bump) {
+		fmt.Printf("Error: unknown bump '%s' (use patch, minor or major)\n", *bump)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	sorted, err := sortModulesByDependencies(modules)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	steps, err := planRelease(*folderPath, sorted, *bump)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(steps) == 0 {
+		fmt.Println("No module changed since its last release.")
+		return
+	}
+	printReleasePlan(steps)
+
+	if dryRun {
+		fmt.Println("\nDry run => nothing changed.")
+		return
+	}
+
+	status, err := gitOutput(*folderPath, "status", "--porcelain")
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if status != "" {
+		fmt.Println("Error: the working tree has uncommitted changes (commit or stash them first).")
+		os.Exit(1)
+	}
+	if push {
+		_, err = gitOutput(*folderPath, "remote", "get-url", releaseRemote)
+		if err != nil {
+			fmt.Println("Error: the tags cannot be pushed:", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println()
+	err = executeRelease(*folderPath, steps, push)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	if push {
+		_, err = gitOutput(*folderPath, "push", releaseRemote, "HEAD")
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Release published successfully.")
+		return
+	}
+
+	tags := make([]string, 0, len(steps))
+	for _, step := range steps {
+		tags = append(tags, step.tag())
+	}
+	fmt.Println("Release created successfully. Publish it with:")
+	fmt.Println("  git push")
+	fmt.Println("  git push " + releaseRemote + " " + strings.Join(tags, " "))
+	if hasReleasedDependents(steps) {
+		fmt.Println("Note: the released dependents require the new versions in go.mod only (go.sum is not updated),")
+		fmt.Println("so they build inside the workspace only. Release with the push option to publish every tag")
+		fmt.Println("before its dependents are updated, which adds the checksums to their go.sum as well.")
+	}
+}
+
+// planRelease determines the modules to release (in dependency order) and their next versions. A module
+// is released if it changed since its last tag or if a module it requires is released.
+func planRelease(rootPath string, sorted []*workspaceModule, forcedBump string) ([]*releaseStep, error) {
+	var steps []*releaseStep
+	released := make(map[string]bool)
+
+	for _, module := range sorted {
+		folder := filepath.Join(rootPath, module.Folder)
+		prefix, err := moduleTagPrefix(folder)
+		if err != nil {
+			return nil, err
+		}
+		current, err := latestModuleVersion(folder)
+		if err != nil {
+			return nil, err
+		}
+
+		bump, reason := bumpNone, ""
+		if current == "" {
+			bump, reason = bumpMinor, "initial release"
+		} else {
+			messages, err := gitOutput(folder, "log", "--format=%s%n%b", prefix+current+"..HEAD", "--", ".")
+			if err != nil {
+				return nil, err
+			}
+			if messages != "" {
+				bump = commitBump(messages)
+				reason = "changed since " + current
+			}
+		}
+		if bump == bumpNone {
+			for _, dep := range module.Deps {
+				if released[dep] {
+					bump, reason = bumpPatch, "dependency released"
+					break
+				}
+			}
+		}
+		if bump == bumpNone {
+			continue
+		}
+		if forcedBump != bumpNone && current != "" {
+			bump = forcedBump
+		}
+
+		next, err := nextVersion(current, bump)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", module.Path, err)
+		}
+		steps = append(steps, &releaseStep{module: module, tagPrefix: prefix, current: current, next: next, reason: reason})
+		released[module.Path] = true
+	}
+	return steps, nil
+}
+
+// commitBump derives the bump from conventional commit messages: breaking changes are a major, features
+// a minor and everything else a patch bump.
+func commitBump(messages string) string {
+	bump := bumpPatch
+	for _, line := range strings.Split(messages, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case breakingCommit.MatchString(line), strings.HasPrefix(line, "BREAKING CHANGE"):
+			return bumpMajor
+		case featureCommit.MatchString(line):
+			bump = bumpMinor
+		}
+	}
+	return bump
+}
+
+// nextVersion returns the version following current. Before v1.0.0 breaking changes only bump the minor
+// version; from v1 on a major bump needs a new module path (/vN) and is not done automatically.
+func nextVersion(current, bump string) (string, error) {
+	if current == "" {
+		if bump == bumpMajor {
+			return "v1.0.0", nil
+		}
+		return "v0.1.0", nil
+	}
+
+	v, ok := parseSemver(current)
+	if !ok {
+		return "", fmt.Errorf("invalid version %s", current)
+	}
+	if v.prerelease != "" {
+		// The release of a pre-release version
+		v.prerelease = ""
+		return v.String(), nil
+	}
+	if bump == bumpMajor && v.major == 0 {
+		bump = bumpMinor
+	}
+
+	switch bump {
+	case bumpMajor:
+		return "", fmt.Errorf("breaking change after %s needs a new major version with module path suffix /v%d, release it manually", current, v.major+1)
+	case bumpMinor:
+		v.minor++
+		v.patch = 0
+	default:
+		v.patch++
+	}
+	return v.String(), nil
+}
+
+// printReleasePlan prints the modules to release in the order they are tagged.
+func printReleasePlan(steps []*releaseStep) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODULE\tCURRENT\tNEXT\tTAG\tREASON")
+	for _, step := range steps {
+		current := step.current
+		if current == "" {
+			current = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", step.module.Path, current, step.next, step.tag(), step.reason)
+	}
+	_ = writer.Flush()
+}
+
+// executeRelease releases the modules in order: the requirements of the released workspace modules are
+// updated and committed, then the module is tagged. With push every tag is pushed right away, so the new
+// versions resolve outside the workspace and the go.sum of the dependents is updated and committed as well.
+func executeRelease(rootPath string, steps []*releaseStep, push bool) error {
+	versions := make(map[string]string, len(steps))
+	for _, step := range steps {
+		folder := filepath.Join(rootPath, step.module.Folder)
+
+		var updated []string
+		for _, dep := range step.module.Deps {
+			version, ok := versions[dep]
+			if !ok {
+				continue
+			}
+			//nolint:gosec // G204: Safe usage - module path and version are determined by vasgotools
+			cmd := exec.Command("go", "mod", "edit", "-require="+dep+"@"+version)
+			cmd.Dir = folder
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("error updating %s in %s: %w\n%s", dep, step.module.Folder, err, output)
+			}
+			updated = append(updated, dep+"@"+version)
+		}
+
+		if len(updated) > 0 {
+			if push {
+				// The pushed versions resolve without the workspace: add their checksums to go.sum
+				cmd := exec.Command("go", "mod", "tidy")
+				cmd.Dir = folder
+				cmd.Env = append(os.Environ(), "GOWORK=off")
+				output, err := cmd.CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("error running go mod tidy in %s: %w\n%s", step.module.Folder, err, output)
+				}
+			}
+			addArgs := []string{"add", "go.mod"}
+			if fileExists(filepath.Join(folder, "go.sum")) {
+				addArgs = append(addArgs, "go.sum")
+			}
+			_, err := gitOutput(folder, addArgs...)
+			if err != nil {
+				return err
+			}
+			message := fmt.Sprintf("Update requirements of %s for release %s\n\n%s", step.module.Path, step.next, strings.Join(updated, "\n"))
+			_, err = gitOutput(folder, "commit", "-m", message)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: requirements updated (%s)\n", step.module.Folder, strings.Join(updated, ", "))
+		}
+
+		_, err := gitOutput(folder, "tag", "-a", step.tag(), "-m", "Release "+step.module.Path+" "+step.next)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: tagged %s\n", step.module.Folder, step.tag())
+		if push {
+			_, err = gitOutput(folder, "push", releaseRemote, step.tag())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: pushed %s\n", step.module.Folder, step.tag())
+		}
+		versions[step.module.Path] = step.next
+	}
+	return nil
+}
+
+// hasReleasedDependents reports whether a released module requires another released module.
+func hasReleasedDependents(steps []*releaseStep) bool {
+	released := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		for _, dep := range step.module.Deps {
+			if released[dep] {
+				return true
+			}
+		}
+		released[step.module.Path] = true
+	}
+	return false
+}
+
+// gitOutput runs git with args in folderPath and returns its trimmed output.
+func gitOutput(folderPath string, args ...string) (string, error) {
+	//nolint:gosec // G204: Safe usage - the arguments are determined by vasgotools
+	cmd := exec.Command("git", args...)
+	cmd.Dir = folderPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error running git %s: %w\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
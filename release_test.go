@@ -0,0 +1,94 @@
+package main
+
+import "testing"
+
+func TestCommitBump(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages string
+		want     string
+	}{
+		{"fix", "fix: handle empty input", bumpPatch},
+		{"other type", "docs: update README\nchore: bump tools", bumpPatch},
+		{"no conventional commit", "Update the filter coefficients", bumpPatch},
+		{"feature", "fix: typo\nfeat: add window functions", bumpMinor},
+		{"feature with scope", "feat(dsp): add window functions", bumpMinor},
+		{"breaking feature", "feat!: remove the legacy API", bumpMajor},
+		{"breaking fix with scope", "fix(api)!: return errors instead of panicking", bumpMajor},
+		{"breaking change footer", "feat: new filter API\n\nBREAKING CHANGE: Filter takes a context", bumpMajor},
+		{"breaking change footer indented", "refactor: filters\n  BREAKING CHANGE: renamed Apply", bumpMajor},
+		{"breaking change wins over feature", "feat: a\nfix!: b\nfeat: c", bumpMajor},
+		{"exclamation mark in description", "fix: crash on input like \"a!:b\"", bumpPatch},
+		{"feature type prefix only", "feature: not conventional", bumpPatch},
+		{"empty", "", bumpPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitBump(tt.messages); got != tt.want {
+				t.Errorf("commitBump() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		bump    string
+		want    string
+		wantErr bool
+	}{
+		{"initial release", "", bumpMinor, "v0.1.0", false},
+		{"initial patch release", "", bumpPatch, "v0.1.0", false},
+		{"initial major release", "", bumpMajor, "v1.0.0", false},
+		{"patch", "v1.2.3", bumpPatch, "v1.2.4", false},
+		{"minor", "v1.2.3", bumpMinor, "v1.3.0", false},
+		{"breaking change before v1", "v0.4.2", bumpMajor, "v0.5.0", false},
+		{"breaking change from v1", "v1.2.3", bumpMajor, "", true},
+		{"release of a pre-release", "v1.3.0-rc.2", bumpPatch, "v1.3.0", false},
+		{"release of a major pre-release", "v2.0.0-beta.1", bumpMajor, "v2.0.0", false},
+		{"invalid version", "1.2.3", bumpPatch, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextVersion(tt.current, tt.bump)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nextVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("nextVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasReleasedDependents(t *testing.T) {
+	a := &workspaceModule{Path: "example.com/a"}
+	b := &workspaceModule{Path: "example.com/b", Deps: []string{"example.com/a"}}
+	c := &workspaceModule{Path: "example.com/c"}
+
+	tests := []struct {
+		name    string
+		modules []*workspaceModule
+		want    bool
+	}{
+		{"dependency and dependent", []*workspaceModule{a, b}, true},
+		{"independent modules", []*workspaceModule{a, c}, false},
+		{"dependent only", []*workspaceModule{b}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := make([]*releaseStep, 0, len(tt.modules))
+			for _, module := range tt.modules {
+				steps = append(steps, &releaseStep{module: module})
+			}
+			if got := hasReleasedDependents(steps); got != tt.want {
+				t.Errorf("hasReleasedDependents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
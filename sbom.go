@@ -0,0 +1,406 @@
+package main
+
+import (
+	"crypto/rand"
+	"debug/buildinfo"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sbomFormatCycloneDX = "cyclonedx"
+	sbomFormatSPDX      = "spdx"
+	spdxNoAssertion     = "NOASSERTION"
+)
+
+// sbomComponent is a module that is part of the software bill of materials.
+type sbomComponent struct {
+	Path    string
+	Version string
+	License string
+}
+
+// sbomInfo holds the data a software bill of materials is created from.
+type sbomInfo struct {
+	Name       string
+	Path       string
+	Version    string
+	Components []sbomComponent
+}
+
+func sbomCommand(args []string) {
+	// Define a flag set for the "sbom" command
+	fs := flag.NewFlagSet("sbom", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the application module (defaults to current working directory)")
+	binaryPath := fs.String("binary", "", "Read the module information from the build info of a built binary instead of the module")
+	format := fs.String("format", sbomFormatCycloneDX, "SBOM format: cyclonedx or spdx")
+	outFile := fs.String("out", "", "File to write the SBOM to (defaults to stdout)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	attach := slices.Contains(positional, "attach")
+
+	if *format != sbomFormatCycloneDX && *format != sbomFormatSPDX {
+		fmt.Printf("Error: unknown format '%s' (use cyclonedx or spdx)\n", *format)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Collect the module information from the binary or from the module graph
+	var info *sbomInfo
+	if *binaryPath != "" {
+		info, err = sbomFromBinary(*binaryPath)
+	} else {
+		info, err = sbomFromModule(*folderPath)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	addComponentLicenses(*folderPath, info)
+
+	// Attach the SBOM to the cross-build output in the bin folder
+	if attach && *outFile == "" {
+		*outFile = filepath.Join(*folderPath, "bin", sbomFileName(info, *format))
+		err = os.MkdirAll(filepath.Dir(*outFile), 0o750)
+		if err != nil {
+			fmt.Println("Error creating bin folder:", err)
+			os.Exit(1)
+		}
+	}
+
+	err = writeSBOMFile(*outFile, *format, info)
+	if err != nil {
+		fmt.Println("Error writing SBOM:", err)
+		os.Exit(1)
+	}
+	if *outFile != "" {
+		fmt.Printf("SBOM written to %s\n", *outFile)
+	}
+}
+
+// sbomFromBinary reads the module information embedded in a built Go binary.
+func sbomFromBinary(binaryPath string) (*sbomInfo, error) {
+	buildInfo, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading build info from %s: %w", binaryPath, err)
+	}
+
+	info := &sbomInfo{
+		Name:    strings.TrimSuffix(filepath.Base(binaryPath), ".exe"),
+		Path:    buildInfo.Main.Path,
+		Version: buildInfo.Main.Version,
+	}
+
+	// Local builds report "(devel)", so fall back to the VCS revision like getVersionString does
+	if info.Version == "" || info.Version == "(devel)" {
+		info.Version = ""
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Version = setting.Value
+			}
+		}
+	}
+
+	for _, dep := range buildInfo.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		info.Components = append(info.Components, sbomComponent{Path: dep.Path, Version: dep.Version})
+	}
+	return info, nil
+}
+
+// sbomFromModule collects the modules linked into the commands of the module in folderPath: the
+// modules of the packages ./... depends on ("go list -deps"), without the main modules of the
+// module or workspace and without modules of the build graph that are not linked.
+func sbomFromModule(folderPath string) (*sbomInfo, error) {
+	mod, err := readGoMod(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("go", "list", "-deps", "-f",
+		"{{with .Module}}{{if not .Main}}{{.Path}} {{.Version}} {{with .Replace}}{{.Version}}{{end}}{{end}}{{end}}", "./...")
+	cmd.Dir = folderPath
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the dependencies of %s: %w", folderPath, err)
+	}
+
+	info := &sbomInfo{
+		Name:    filepath.Base(mod.Module.Path),
+		Path:    mod.Module.Path,
+		Version: gitDescribeVersion(folderPath),
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+
+		// The version of a replacement module replaces the required version
+		component := sbomComponent{Path: fields[0]}
+		if len(fields) > 1 {
+			component.Version = fields[len(fields)-1]
+		}
+		info.Components = append(info.Components, component)
+	}
+	sort.Slice(info.Components, func(i, j int) bool { return info.Components[i].Path < info.Components[j].Path })
+	return info, nil
+}
+
+// gitDescribeVersion returns the version of the module in folderPath the way the build scripts determine it.
+// An empty string is returned if the version cannot be determined (e.g. no tag exists).
+func gitDescribeVersion(folderPath string) string {
+	cmd := exec.Command("git", "describe", "--tags")
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// addComponentLicenses detects the licenses of all components found in the module cache.
+func addComponentLicenses(folderPath string, info *sbomInfo) {
+	for i := range info.Components {
+		component := &info.Components[i]
+		component.License = spdxNoAssertion
+		if component.Version == "" {
+			continue
+		}
+		dir, err := moduleDir(folderPath, listedModule{Path: component.Path, Version: component.Version})
+		if err != nil {
+			continue
+		}
+		if license, _, _ := detectLicense(dir); license != licenseUnknown {
+			component.License = license
+		}
+	}
+}
+
+// sbomFileName returns the name of the SBOM file attached to the cross-build output.
+func sbomFileName(info *sbomInfo, format string) string {
+	if format == sbomFormatSPDX {
+		return info.Name + ".spdx.json"
+	}
+	return info.Name + ".cdx.json"
+}
+
+// writeSBOMFile writes the SBOM in the given format to outFile (or stdout, if outFile is empty).
+func writeSBOMFile(outFile, format string, info *sbomInfo) error {
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		//nolint:gosec // G304: Safe usage - the SBOM file is chosen by the user
+		file, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var document any
+	if format == sbomFormatSPDX {
+		document = newSPDXDocument(info)
+	} else {
+		document = newCycloneDXDocument(info)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// packageURL returns the package URL (purl) of a Go module.
+func packageURL(path, version string) string {
+	purl := "pkg:golang/" + path
+	if version != "" {
+		purl += "@" + version
+	}
+	return purl
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[:])
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
+// CycloneDX (https://cyclonedx.org/docs/1.5/json/)
+
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cycloneDXComponent `json:"components"`
+	} `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXComponent struct {
+	Type     string             `json:"type"`
+	BOMRef   string             `json:"bom-ref,omitempty"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	PURL     string             `json:"purl,omitempty"`
+	Licenses []cycloneDXLicense `json:"licenses,omitempty"`
+}
+
+type cycloneDXLicense struct {
+	License struct {
+		ID string `json:"id"`
+	} `json:"license"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func newCycloneDXDocument(info *sbomInfo) *cycloneDXDocument {
+	mainRef := packageURL(info.Path, info.Version)
+	document := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Components:   []cycloneDXComponent{},
+	}
+	document.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	document.Metadata.Tools.Components = []cycloneDXComponent{{Type: "application", Name: "vasgotools", Version: getVersionString()}}
+	document.Metadata.Component = cycloneDXComponent{Type: "application", BOMRef: mainRef, Name: info.Path, Version: info.Version, PURL: mainRef}
+
+	dependency := cycloneDXDependency{Ref: mainRef, DependsOn: []string{}}
+	for _, component := range info.Components {
+		ref := packageURL(component.Path, component.Version)
+		entry := cycloneDXComponent{Type: "library", BOMRef: ref, Name: component.Path, Version: component.Version, PURL: ref}
+		if component.License != spdxNoAssertion {
+			var license cycloneDXLicense
+			license.License.ID = component.License
+			entry.Licenses = []cycloneDXLicense{license}
+		}
+		document.Components = append(document.Components, entry)
+		dependency.DependsOn = append(dependency.DependsOn, ref)
+	}
+	document.Dependencies = []cycloneDXDependency{dependency}
+	return document
+}
+
+// SPDX (https://spdx.github.io/spdx-spec/v2.3/)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func newSPDXDocument(info *sbomInfo) *spdxDocument {
+	document := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              info.Name,
+		DocumentNamespace: fmt.Sprintf("https://%s/spdx/%s-%s", info.Path, info.Name, newUUID()),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: vasgotools-" + getVersionString()},
+		},
+	}
+
+	mainID := "SPDXRef-Package-main"
+	document.Packages = append(document.Packages, newSPDXPackage(mainID, info.Path, info.Version, spdxNoAssertion))
+	document.Relationships = append(document.Relationships, spdxRelationship{SPDXElementID: document.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: mainID})
+
+	for i, component := range info.Components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		document.Packages = append(document.Packages, newSPDXPackage(id, component.Path, component.Version, component.License))
+		document.Relationships = append(document.Relationships, spdxRelationship{SPDXElementID: mainID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: id})
+	}
+	return document
+}
+
+func newSPDXPackage(id, path, version, license string) spdxPackage {
+	pkg := spdxPackage{
+		Name:             path,
+		SPDXID:           id,
+		VersionInfo:      version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  license,
+		ExternalRefs: []spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  packageURL(path, version),
+		}},
+	}
+	return pkg
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// crossBuildConfigFileName is the file configuring the targets of the commands built by the cross-build scripts.
+const crossBuildConfigFileName = "cross-build.conf"
+
+const crossBuildConfigHeader = `# Targets of the commands (package main) built by cross-build.sh and cross-build.bat.
+# One command folder per line, relative to the module, followed by its targets (<os>/<arch> ...).
+# Commands without targets use the default targets, which can be replaced by a "default" line:
+# default windows/amd64 linux/amd64 darwin/amd64 darwin/arm64
+`
+
+// validatePackagePath checks that relPath is a relative slash-separated folder inside the module.
+func validatePackagePath(relPath string) error {
+	if relPath == "" || path.IsAbs(relPath) || strings.Contains(relPath, `\`) {
+		return fmt.Errorf("invalid package path '%s' (use a relative path like dsp/filter)", relPath)
+	}
+	for _, element := range strings.Split(relPath, "/") {
+		if element == "" || element == "." || element == ".." || strings.HasPrefix(element, "_") {
+			return fmt.Errorf("invalid package path '%s' (use a relative path like dsp/filter)", relPath)
+		}
+	}
+	return nil
+}
+
+// ensureEmptyPackageFolder creates the folder of a new package. A folder that already contains
+// Go files is only accepted with force.
+func ensureEmptyPackageFolder(folderPath string, force bool) error {
+	goFiles, err := filepath.Glob(filepath.Join(folderPath, "*.go"))
+	if err != nil {
+		return err
+	}
+	if len(goFiles) > 0 && !force {
+		return fmt.Errorf("%s already contains Go files (use 'force' to overwrite)", folderPath)
+	}
+	return os.MkdirAll(folderPath, 0o750)
+}
+
+// addPackageCommand adds the package relPath with doc.go, starter code and tests to the module in folderPath.
+func addPackageCommand(folderPath, relPath string, force bool) {
+	if err := validatePackagePath(relPath); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	mod, err := readGoMod(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	packagePath := filepath.Join(folderPath, filepath.FromSlash(relPath))
+	err = ensureEmptyPackageFolder(packagePath, force)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	importPath := mod.Module.Path + "/" + relPath
+	packageName, err := createPackageFiles(packagePath, importPath)
+	if err != nil {
+		fmt.Println("Error creating package files:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Package %s (%s) created successfully.\n", packageName, importPath)
+}
+
+// addCmdCommand adds the command cmd/<name> to the module in folderPath and lists it in the
+// cross-build configuration, where its targets can be set.
+func addCmdCommand(folderPath, name, kind string, force bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) || validatePackagePath(name) != nil {
+		fmt.Printf("Error: invalid command name '%s'\n", name)
+		os.Exit(1)
+	}
+	if err := validateAppKind(kind); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	mod, err := readGoMod(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	commandPath := filepath.Join(folderPath, "cmd", name)
+	err = ensureEmptyPackageFolder(commandPath, force)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	err = writeMainFiles(commandPath, mod.Module.Path, kind)
+	if err != nil {
+		fmt.Println("Error writing main.go:", err)
+		os.Exit(1)
+	}
+	if !fileExists(filepath.Join(folderPath, "internal", "version", "version.go")) {
+		err = createVersionPackage(folderPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("internal/version created successfully.")
+	}
+	fmt.Printf("cmd/%s/main.go (%s) created successfully.\n", name, kind)
+
+	err = addCrossBuildCommand(folderPath, "cmd/"+name)
+	if err != nil {
+		fmt.Println("Error updating the cross-build configuration:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("cmd/%s added to %s.\n", name, crossBuildConfigFileName)
+
+	if kind == appKindService {
+		err = createServiceDeployFiles(folderPath, name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Service files in %s created successfully.\n", filepath.Join(deployFolder, name))
+	}
+}
+
+// addCrossBuildCommand lists the command folder relPath (with the default targets) in the
+// cross-build configuration of the module in folderPath (created if missing).
+func addCrossBuildCommand(folderPath, relPath string) error {
+	configPath := filepath.Join(folderPath, crossBuildConfigFileName)
+	//nolint:gosec // G304: Safe usage - configPath is controlled by the application
+	content, err := os.ReadFile(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(content) == 0 {
+		content = []byte(crossBuildConfigHeader)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == relPath {
+			return nil
+		}
+	}
+	if !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, relPath+"\n"...)
+	return os.WriteFile(configPath, content, 0o600)
+}
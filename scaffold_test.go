@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestValidatePackagePath(t *testing.T) {
+	tests := []struct {
+		relPath string
+		wantErr bool
+	}{
+		{"filter", false},
+		{"dsp/filter", false},
+		{"cmd/server", false},
+		{"", true},
+		{"/dsp/filter", true},
+		{`dsp\filter`, true},
+		{"dsp//filter", true},
+		{"dsp/filter/", true},
+		{"./dsp", true},
+		{"../dsp", true},
+		{"dsp/../../x", true},
+		{"_ignored/filter", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			if err := validatePackagePath(tt.relPath); (err != nil) != tt.wantErr {
+				t.Errorf("validatePackagePath(%q) error = %v, wantErr %v", tt.relPath, err, tt.wantErr)
+			}
+		})
+	}
+}
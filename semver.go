@@ -0,0 +1,159 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version like v1.2.3-rc.1 (build metadata is dropped).
+type semver struct {
+	major, minor, patch int
+	prerelease          string
+}
+
+// parseSemver parses a Go module version (with leading "v").
+func parseSemver(version string) (semver, bool) {
+	var v semver
+	rest, ok := strings.CutPrefix(version, "v")
+	if !ok {
+		return v, false
+	}
+	rest, _, _ = strings.Cut(rest, "+")
+	rest, v.prerelease, _ = strings.Cut(rest, "-")
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return v, false
+		}
+		numbers[i] = number
+	}
+	v.major, v.minor, v.patch = numbers[0], numbers[1], numbers[2]
+	return v, true
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	if v.prerelease != "" {
+		s += "-" + v.prerelease
+	}
+	return s
+}
+
+// compareSemver compares two module versions following the semver precedence rules.
+// Invalid versions sort before valid ones.
+func compareSemver(a, b string) int {
+	va, okA := parseSemver(a)
+	vb, okB := parseSemver(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	if c := cmp.Compare(va.major, vb.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(va.minor, vb.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(va.patch, vb.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+// comparePrerelease compares pre-release identifiers: a version without pre-release has the higher
+// precedence, numeric identifiers are compared numerically and sort before alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, errA := strconv.Atoi(partsA[i])
+		numberB, errB := strconv.Atoi(partsB[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(numberA, numberB)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(partsA[i], partsB[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(partsA), len(partsB))
+}
+
+// moduleTagPrefix returns the prefix of the release tags of the module in folderPath: the folder
+// relative to the Git repository root (e.g. "ext/lib1/"), or "" for a module at the repository root.
+func moduleTagPrefix(folderPath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-prefix")
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error determining the Git folder of %s: %w", folderPath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// latestModuleVersion returns the highest version the module in folderPath is tagged with
+// (tags <prefix>vX.Y.Z, see moduleTagPrefix). An empty string is returned if there is no such tag.
+func latestModuleVersion(folderPath string) (string, error) {
+	versions, err := moduleVersions(folderPath)
+	if err != nil || len(versions) == 0 {
+		return "", err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// moduleVersions returns the versions the module in folderPath is tagged with in ascending order.
+func moduleVersions(folderPath string) ([]string, error) {
+	prefix, err := moduleTagPrefix(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // G204: Safe usage - the prefix is a folder of the repository
+	cmd := exec.Command("git", "tag", "--list", prefix+"v*")
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the tags of %s: %w", folderPath, err)
+	}
+
+	var versions []string
+	for _, tag := range strings.Fields(string(output)) {
+		version := strings.TrimPrefix(tag, prefix)
+		if _, ok := parseSemver(version); ok {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortFunc(versions, compareSemver)
+	return versions, nil
+}
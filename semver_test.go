@@ -0,0 +1,98 @@
+package main
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		version string
+		want    semver
+		wantOK  bool
+	}{
+		{"v1.2.3", semver{major: 1, minor: 2, patch: 3}, true},
+		{"v0.0.0", semver{}, true},
+		{"v2.0.0-rc.1", semver{major: 2, prerelease: "rc.1"}, true},
+		{"v1.0.0-beta+exp.sha.5114f85", semver{major: 1, prerelease: "beta"}, true},
+		{"v1.0.0+20130313144700", semver{major: 1}, true},
+		{"v0.0.0-20240101120000-abcdef123456", semver{prerelease: "20240101120000-abcdef123456"}, true},
+		{"1.2.3", semver{}, false},
+		{"v1.2", semver{}, false},
+		{"v1.2.3.4", semver{}, false},
+		{"v1.x.3", semver{}, false},
+		{"", semver{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, ok := parseSemver(tt.version)
+			if ok != tt.wantOK {
+				t.Fatalf("parseSemver() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseSemver() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemverString(t *testing.T) {
+	for _, version := range []string{"v1.2.3", "v0.1.0", "v2.0.0-rc.1"} {
+		v, ok := parseSemver(version)
+		if !ok {
+			t.Fatalf("parseSemver(%s) failed", version)
+		}
+		if got := v.String(); got != version {
+			t.Errorf("String() = %s, want %s", got, version)
+		}
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v1.3.0", "v1.2.9", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.10.0", "v1.9.0", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0", "v1.0.0-rc.1", 1},
+		{"v1.0.0+build.1", "v1.0.0", 0},
+		{"v1.0.0", "invalid", 1},
+		{"invalid", "v0.0.1", -1},
+		{"a", "b", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := compareSemver(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareSemver(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparePrereleaseOrdering(t *testing.T) {
+	// The precedence example of the semver specification (https://semver.org/#spec-item-11)
+	ordered := []string{
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		lower, higher := ordered[i], ordered[i+1]
+		if got := compareSemver(lower, higher); got != -1 {
+			t.Errorf("compareSemver(%s, %s) = %d, want -1", lower, higher, got)
+		}
+		if got := compareSemver(higher, lower); got != 1 {
+			t.Errorf("compareSemver(%s, %s) = %d, want 1", higher, lower, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// requirement is a require directive of a workspace module.
+type requirement struct {
+	module   *workspaceModule
+	version  string
+	indirect bool
+}
+
+// requirementSkew describes a dependency required in different versions by the workspace modules,
+// or a sibling module required in a version older than its latest tag.
+type requirementSkew struct {
+	path         string
+	requirements []requirement
+	// latest is the latest tagged version of a sibling module ("" for third-party dependencies)
+	latest string
+	// target is the version the requirements are aligned to
+	target string
+}
+
+func workSkewCommand(args []string) {
+	// Define a flag set for the "work skew" command
+	fs := flag.NewFlagSet("work skew", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace (defaults to current working directory)")
+	align := fs.Bool("align", false, "Rewrite the go.mod files to require a single version of every dependency")
+	use := fs.String("use", "", "Comma-separated list of module@version to align to (default: the highest version)")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	targets, err := parseVersionList(*use)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	skews := findRequirementSkews(*folderPath, modules, targets)
+	for path := range targets {
+		if !slices.ContainsFunc(skews, func(skew *requirementSkew) bool { return skew.path == path }) {
+			fmt.Printf("Warning: %s is not required in different versions => --use ignored.\n", path)
+		}
+	}
+	if len(skews) == 0 {
+		fmt.Println("No version skew found.")
+		return
+	}
+	printRequirementSkews(skews)
+
+	if !*align {
+		fmt.Printf("\n%d dependencies with version skew found (use --align to fix).\n", len(skews))
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	err = alignRequirements(*folderPath, skews)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Requirements aligned successfully.")
+	fmt.Println("Run 'vasgotools foreach -- go mod tidy' to update the go.sum files.")
+}
+
+// parseVersionList parses a comma-separated list of module@version.
+func parseVersionList(list string) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		path, version, ok := strings.Cut(item, "@")
+		if _, valid := parseSemver(version); !ok || !valid {
+			return nil, fmt.Errorf("invalid module version '%s' (use module@vX.Y.Z)", item)
+		}
+		versions[path] = version
+	}
+	return versions, nil
+}
+
+// findRequirementSkews returns the dependencies required in more than one version, and the sibling
+// modules required in a version older than their latest tag, sorted by module path. The target version
+// is taken from targets or is the highest version.
+func findRequirementSkews(rootPath string, modules []*workspaceModule, targets map[string]string) []*requirementSkew {
+	requirements := make(map[string][]requirement)
+	for _, module := range modules {
+		for _, require := range module.Mod.Require {
+			requirements[require.Path] = append(requirements[require.Path], requirement{module: module, version: require.Version, indirect: require.Indirect})
+		}
+	}
+
+	siblings := modulesByPath(modules)
+	var skews []*requirementSkew
+	for path, reqs := range requirements {
+		skew := &requirementSkew{path: path, requirements: reqs}
+		for _, req := range reqs {
+			if skew.target == "" || compareSemver(req.version, skew.target) > 0 {
+				skew.target = req.version
+			}
+		}
+		hasSkew := slices.ContainsFunc(reqs, func(req requirement) bool { return req.version != skew.target })
+
+		if sibling, ok := siblings[path]; ok {
+			// Without a Git repository or tags the latest version is unknown
+			skew.latest, _ = latestModuleVersion(filepath.Join(rootPath, sibling.Folder))
+			if skew.latest != "" && compareSemver(skew.latest, skew.target) > 0 {
+				skew.target = skew.latest
+				hasSkew = true
+			}
+		}
+		if !hasSkew {
+			continue
+		}
+		if target, ok := targets[path]; ok {
+			skew.target = target
+		}
+		skews = append(skews, skew)
+	}
+
+	slices.SortFunc(skews, func(a, b *requirementSkew) int { return strings.Compare(a.path, b.path) })
+	return skews
+}
+
+// printRequirementSkews prints the required versions of every skewed dependency.
+func printRequirementSkews(skews []*requirementSkew) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DEPENDENCY\tVERSION\tREQUIRED BY")
+	for _, skew := range skews {
+		for _, req := range skew.requirements {
+			requiredBy := filepath.ToSlash(req.module.Folder)
+			if req.indirect {
+				requiredBy += " (indirect)"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", skew.path, req.version, requiredBy)
+		}
+		if skew.latest != "" {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", skew.path, skew.latest, "(latest tag)")
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", skew.path, skew.target, "=> target")
+	}
+	_ = writer.Flush()
+}
+
+// alignRequirements updates the requirements to the target versions using "go mod edit".
+func alignRequirements(rootPath string, skews []*requirementSkew) error {
+	for _, skew := range skews {
+		for _, req := range skew.requirements {
+			if req.version == skew.target {
+				continue
+			}
+			//nolint:gosec // G204: Safe usage - module path and version are taken from go.mod files or validated
+			cmd := exec.Command("go", "mod", "edit", "-require="+skew.path+"@"+skew.target)
+			cmd.Dir = filepath.Join(rootPath, req.module.Folder)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("error updating %s in %s: %w\n%s", skew.path, req.module.Folder, err, output)
+			}
+			fmt.Printf("%s: %s %s -> %s\n", filepath.ToSlash(req.module.Folder), skew.path, req.version, skew.target)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	vendorPolicyCommit = "commit"
+	vendorPolicyIgnore = "ignore"
+)
+
+const (
+	vendorGitattributesComment = "# Vendored dependencies: stored as they are (no line ending conversion)"
+	vendorGitattributesLine    = "/vendor/** -text linguist-vendored"
+	vendorGitignoreComment     = "# Vendored dependencies (created by vasgotools vendor)"
+	vendorGitignoreLine        = "/vendor/"
+)
+
+func printVendorUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  vasgotools.exe vendor [--path <module or workspace>] [--policy commit|ignore]")
+	fmt.Println("  vasgotools.exe vendor verify [--path <module or workspace>]")
+}
+
+// vendorCommand creates or updates the vendor folder of a module or workspace and verifies it.
+func vendorCommand(args []string) {
+	verifyOnly := len(args) > 0 && args[0] == "verify"
+	if verifyOnly {
+		args = args[1:]
+	}
+
+	// Define a flag set for the "vendor" command
+	fs := flag.NewFlagSet("vendor", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the module or workspace (defaults to current working directory)")
+	policy := fs.String("policy", vendorPolicyCommit, "Git policy for the vendor folder: commit or ignore")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+	if len(positional) > 0 {
+		fmt.Printf("Error: unexpected argument '%s'\n", positional[0])
+		printVendorUsage()
+		os.Exit(1)
+	}
+	if *policy != vendorPolicyCommit && *policy != vendorPolicyIgnore {
+		fmt.Printf("Error: unknown policy '%s' (use commit or ignore)\n", *policy)
+		os.Exit(1)
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	workspace := isWorkspace(*folderPath)
+
+	if !verifyOnly {
+		cmd := exec.Command("go", vendorArgs(workspace)...)
+		cmd.Dir = *folderPath
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		fmt.Println("Running command:", cmd.String())
+		if err := cmd.Run(); err != nil {
+			fmt.Println("Error creating the vendor folder:", err)
+			os.Exit(1)
+		}
+
+		err = applyVendorPolicy(*folderPath, *policy)
+		if err != nil {
+			fmt.Println("Error applying the vendor policy:", err)
+			os.Exit(1)
+		}
+		if *policy == vendorPolicyCommit {
+			fmt.Println("vendor folder is committed (.gitattributes: no line ending conversion).")
+		} else {
+			fmt.Println("vendor folder is ignored (.gitignore).")
+		}
+	}
+
+	problems, err := verifyVendor(*folderPath, workspace)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(problems) > 0 {
+		fmt.Println("The vendor folder does not match the modules verified against go.sum:")
+		for _, problem := range problems {
+			fmt.Println("  " + problem)
+		}
+		fmt.Println("Run 'vasgotools vendor' to update it.")
+		os.Exit(1)
+	}
+	fmt.Println("vendor folder verified successfully.")
+}
+
+// vendorArgs returns the arguments of the go command vendoring a workspace or module.
+func vendorArgs(workspace bool) []string {
+	if workspace {
+		return []string{"work", "vendor"}
+	}
+	return []string{"mod", "vendor"}
+}
+
+// verifyVendor checks the module cache against go.sum ("go mod verify" in every module) and
+// compares the vendor folder with a fresh copy vendored from the verified modules. The
+// differences are returned as "modified: <file>", "missing: <file>" and "unexpected: <file>".
+func verifyVendor(folderPath string, workspace bool) ([]string, error) {
+	vendorPath := filepath.Join(folderPath, "vendor")
+	if !fileExists(filepath.Join(vendorPath, "modules.txt")) {
+		return nil, fmt.Errorf("no vendor folder in %s (run 'vasgotools vendor' first)", folderPath)
+	}
+
+	folders := []string{"."}
+	if workspace {
+		var err error
+		folders, err = workspaceModuleFolders(folderPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, folder := range folders {
+		cmd := exec.Command("go", "mod", "verify")
+		cmd.Dir = filepath.Join(folderPath, folder)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("go mod verify failed in %s: %w\n%s", folder, err, output)
+		}
+	}
+
+	tempPath, err := os.MkdirTemp("", "vasgotools-vendor-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tempPath) }()
+
+	expectedPath := filepath.Join(tempPath, "vendor")
+	//nolint:gosec // G204: Safe usage - the arguments are determined by vasgotools
+	cmd := exec.Command("go", append(vendorArgs(workspace), "-o", expectedPath)...)
+	cmd.Dir = folderPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error vendoring the verified modules (are they in the module cache?): %w\n%s", err, output)
+	}
+
+	return compareFolders(expectedPath, vendorPath)
+}
+
+// compareFolders compares the files of the folder actualPath with those of expectedPath.
+func compareFolders(expectedPath, actualPath string) ([]string, error) {
+	expected, err := listFiles(expectedPath)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := listFiles(actualPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, file := range expected {
+		if !slices.Contains(actual, file) {
+			problems = append(problems, "missing: "+file)
+			continue
+		}
+		//nolint:gosec // G304: Safe usage - the files are listed by the application
+		expectedContent, err := os.ReadFile(filepath.Join(expectedPath, file))
+		if err != nil {
+			return nil, err
+		}
+		//nolint:gosec // G304: Safe usage - the files are listed by the application
+		actualContent, err := os.ReadFile(filepath.Join(actualPath, file))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(expectedContent, actualContent) {
+			problems = append(problems, "modified: "+file)
+		}
+	}
+	for _, file := range actual {
+		if !slices.Contains(expected, file) {
+			problems = append(problems, "unexpected: "+file)
+		}
+	}
+	return problems, nil
+}
+
+// listFiles returns the slash-separated paths of all files below folderPath in lexical order.
+func listFiles(folderPath string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(folderPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relativePath))
+		return nil
+	})
+	return files, err
+}
+
+// applyVendorPolicy updates .gitattributes and .gitignore in folderPath: with policy "commit" the
+// vendor folder is stored without line ending conversion (so it matches the module zips), with
+// policy "ignore" it is excluded from Git.
+func applyVendorPolicy(folderPath, policy string) error {
+	gitignorePath := filepath.Join(folderPath, ".gitignore")
+	gitattributesPath := filepath.Join(folderPath, ".gitattributes")
+	if policy == vendorPolicyIgnore {
+		err := removeFileLine(gitattributesPath, vendorGitattributesComment, vendorGitattributesLine)
+		if err != nil {
+			return err
+		}
+		return appendFileLine(gitignorePath, vendorGitignoreComment, vendorGitignoreLine)
+	}
+
+	err := removeFileLine(gitignorePath, vendorGitignoreComment, vendorGitignoreLine)
+	if err != nil {
+		return err
+	}
+	return appendFileLine(gitattributesPath, vendorGitattributesComment, vendorGitattributesLine)
+}
+
+// appendFileLine appends line (preceded by comment) to the file at filePath unless it already
+// contains it. The file is created if missing.
+func appendFileLine(filePath, comment, line string) error {
+	//nolint:gosec // G304: Safe usage - filePath is controlled by the application
+	content, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if slices.Contains(strings.Split(text, "\n"), line) {
+		return nil
+	}
+
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if text != "" {
+		text += "\n"
+	}
+	text += comment + "\n" + line + "\n"
+	return os.WriteFile(filePath, []byte(text), 0o600)
+}
+
+// removeFileLine removes line and the preceding comment from the file at filePath (if it exists).
+func removeFileLine(filePath, comment, line string) error {
+	//nolint:gosec // G304: Safe usage - filePath is controlled by the application
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	index := slices.Index(lines, line)
+	if index < 0 {
+		return nil
+	}
+	start := index
+	if start > 0 && lines[start-1] == comment {
+		start--
+		if start > 0 && lines[start-1] == "" {
+			start--
+		}
+	}
+	lines = slices.Delete(lines, start, index+1)
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0o600)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	vscodeFolder            = ".vscode"
+	vscodeWorkspaceFileExt  = ".code-workspace"
+	vscodeSettingsFile      = "settings.json"
+	vscodeTasksFile         = "tasks.json"
+	vscodeLaunchFile        = "launch.json"
+	vscodeGolangciConfigVar = "${workspaceFolder}/golangci.yml"
+)
+
+// vscodeTask is a task of the tasks.json file.
+type vscodeTask struct {
+	Label          string            `json:"label"`
+	Type           string            `json:"type"`
+	Command        string            `json:"command"`
+	Windows        *vscodeTaskWindow `json:"windows,omitempty"`
+	Group          any               `json:"group,omitempty"`
+	ProblemMatcher []string          `json:"problemMatcher"`
+}
+
+// vscodeTaskWindow holds the Windows specific command of a task.
+type vscodeTaskWindow struct {
+	Command string `json:"command"`
+}
+
+// vscodeLaunchConfiguration is a debug configuration of the launch.json file.
+type vscodeLaunchConfiguration struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Request string   `json:"request"`
+	Mode    string   `json:"mode"`
+	Program string   `json:"program"`
+	Args    []string `json:"args"`
+}
+
+// vscodeSettings returns the settings for gopls and the golangci-lint integration.
+func vscodeSettings() map[string]any {
+	return map[string]any{
+		"go.useLanguageServer": true,
+		"go.lintTool":          "golangci-lint",
+		"go.lintFlags":         []string{"--config=" + vscodeGolangciConfigVar},
+		"go.lintOnSave":        "package",
+		"go.vetOnSave":         "package",
+		"gopls": map[string]any{
+			"ui.semanticTokens":             true,
+			"ui.diagnostic.staticcheck":     true,
+			"ui.diagnostic.analyses":        map[string]bool{"unusedparams": true, "shadow": true},
+			"build.directoryFilters":        []string{"-bin", "-vendor"},
+			"ui.completion.usePlaceholders": true,
+		},
+		"[go]": map[string]any{
+			"editor.formatOnSave": true,
+			"editor.codeActionsOnSave": map[string]string{
+				"source.organizeImports": "explicit",
+			},
+		},
+	}
+}
+
+// vscodeTasks returns the build, analyze and cross-build tasks.
+func vscodeTasks() map[string]any {
+	return map[string]any{
+		"version": "2.0.0",
+		"tasks": []vscodeTask{
+			{
+				Label:          "build",
+				Type:           "shell",
+				Command:        "go build ./...",
+				Group:          map[string]any{"kind": "build", "isDefault": true},
+				ProblemMatcher: []string{"$go"},
+			},
+			{
+				Label:          "analyze",
+				Type:           "shell",
+				Command:        "./build.sh",
+				Windows:        &vscodeTaskWindow{Command: ".\\build.bat"},
+				Group:          "test",
+				ProblemMatcher: []string{"$go"},
+			},
+			{
+				Label:          "cross-build",
+				Type:           "shell",
+				Command:        "./cross-build.sh",
+				Windows:        &vscodeTaskWindow{Command: ".\\cross-build.bat"},
+				Group:          "build",
+				ProblemMatcher: []string{"$go"},
+			},
+			{
+				Label:          "test",
+				Type:           "shell",
+				Command:        "go test ./...",
+				Group:          map[string]any{"kind": "test", "isDefault": true},
+				ProblemMatcher: []string{"$go"},
+			},
+		},
+	}
+}
+
+// vscodeLaunch returns the debug configurations for the main package of an application.
+func vscodeLaunch(name string) map[string]any {
+	return map[string]any{
+		"version": "0.2.0",
+		"configurations": []vscodeLaunchConfiguration{
+			{
+				Name:    "Launch " + name,
+				Type:    "go",
+				Request: "launch",
+				Mode:    "auto",
+				Program: "${workspaceFolder}",
+				Args:    []string{},
+			},
+			{
+				Name:    "Test " + name,
+				Type:    "go",
+				Request: "launch",
+				Mode:    "test",
+				Program: "${workspaceFolder}",
+				Args:    []string{},
+			},
+		},
+	}
+}
+
+// writeJSONFile writes content as indented JSON to filePath.
+func writeJSONFile(filePath string, content any) error {
+	data, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", filepath.Base(filePath), err)
+	}
+	data = append(data, '\n')
+	err = os.WriteFile(filePath, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Base(filePath), err)
+	}
+	return nil
+}
+
+// createVSCodeConfig creates the .vscode folder with settings.json, tasks.json and (for applications) launch.json.
+func createVSCodeConfig(folderPath, name string, isLibrary bool) error {
+	vscodePath := filepath.Join(folderPath, vscodeFolder)
+	err := os.MkdirAll(vscodePath, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating %s folder: %w", vscodeFolder, err)
+	}
+
+	err = writeJSONFile(filepath.Join(vscodePath, vscodeSettingsFile), vscodeSettings())
+	if err != nil {
+		return err
+	}
+	err = writeJSONFile(filepath.Join(vscodePath, vscodeTasksFile), vscodeTasks())
+	if err != nil {
+		return err
+	}
+	if isLibrary {
+		return nil
+	}
+	return writeJSONFile(filepath.Join(vscodePath, vscodeLaunchFile), vscodeLaunch(name))
+}
+
+// vscodeWorkspaceFileName returns the name of the multi-root workspace file for the workspace in folderPath.
+func vscodeWorkspaceFileName(folderPath string) string {
+	return filepath.Base(folderPath) + vscodeWorkspaceFileExt
+}
+
+// createVSCodeWorkspace creates a multi-root .code-workspace file listing every module of the workspace.
+// It returns the name of the .code-workspace file.
+func createVSCodeWorkspace(folderPath string, moduleFolders []string) (string, error) {
+	type workspaceFolder struct {
+		Name string `json:"name,omitempty"`
+		Path string `json:"path"`
+	}
+
+	// The workspace root comes first, so go.work and the workspace level files are reachable
+	folders := []workspaceFolder{{Name: filepath.Base(folderPath), Path: "."}}
+	for _, moduleFolder := range moduleFolders {
+		if moduleFolder == "." {
+			continue
+		}
+		folders = append(folders, workspaceFolder{Path: filepath.ToSlash(moduleFolder)})
+	}
+
+	settings := vscodeSettings()
+	settings["files.exclude"] = map[string]bool{"**/bin": true}
+
+	workspaceFileName := vscodeWorkspaceFileName(folderPath)
+	return workspaceFileName, writeJSONFile(filepath.Join(folderPath, workspaceFileName), map[string]any{
+		"folders":  folders,
+		"settings": settings,
+	})
+}
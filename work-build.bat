@@ -0,0 +1,19 @@
+@echo off
+REM Build script for the Go workspace
+REM Builds all modules of the workspace (go.work) in dependency order, independent modules in parallel,
+REM and prints a summary table with the status and duration of every module.
+REM
+REM Usage:
+REM   build.bat [--parallel <n>] [--mode script|go] [quiet]
+REM
+REM Requires vasgotools (go install github.com/mbbm-slb/vasgotools@latest), falls back to "go run".
+
+cd /d "%~dp0"
+
+where vasgotools >nul 2>nul
+if %errorlevel% equ 0 (
+    vasgotools work build %*
+) else (
+    go run github.com/mbbm-slb/vasgotools@latest work build %*
+)
+exit /b %errorlevel%
@@ -0,0 +1,17 @@
+#!/bin/bash
+# Build script for the Go workspace
+# Builds all modules of the workspace (go.work) in dependency order, independent modules in parallel,
+# and prints a summary table with the status and duration of every module.
+#
+# Usage:
+#   ./build.sh [--parallel <n>] [--mode script|go] [quiet]
+#
+# Requires vasgotools (go install github.com/mbbm-slb/vasgotools@latest), falls back to "go run".
+
+cd "$(dirname "$0")" || exit 1
+
+if command -v vasgotools >/dev/null 2>&1; then
+    vasgotools work build "$@"
+else
+    go run github.com/mbbm-slb/vasgotools@latest work build "$@"
+fi
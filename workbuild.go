@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	buildModeScript = "script"
+	buildModeGo     = "go"
+
+	moduleStatusOK      = "ok"
+	moduleStatusFailed  = "failed"
+	moduleStatusSkipped = "skipped"
+)
+
+// moduleBuildResult holds the outcome of building a single workspace module.
+type moduleBuildResult struct {
+	module   *workspaceModule
+	status   string
+	duration time.Duration
+	output   []byte
+	err      error
+}
+
+func workBuildCommand(args []string) {
+	// Define a flag set for the "work build" command
+	fs := flag.NewFlagSet("work build", flag.ExitOnError)
+	folderPath := fs.String("path", "", "Path to the workspace (defaults to current working directory)")
+	parallel := fs.Int("parallel", runtime.NumCPU(), "Maximum number of modules built in parallel")
+	mode := fs.String("mode", buildModeScript, "Build mode: 'script' runs the build script of each module (build.sh/build.bat), 'go' runs 'go build ./...'")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		fmt.Println("Error parsing flags:", err)
+		return
+	}
+
+	// Check for optional flags
+	quiet := slices.Contains(positional, "quiet")
+
+	if *mode != buildModeScript && *mode != buildModeGo {
+		fmt.Printf("Error: unknown build mode '%s' (use script or go)\n", *mode)
+		os.Exit(1)
+	}
+	if *parallel < 1 {
+		*parallel = 1
+	}
+
+	// Use the current working directory if no path is provided
+	err = setDefaultFolderPath(folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	modules, err := loadWorkspaceModules(*folderPath)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(modules) == 0 {
+		fmt.Println("No modules found.")
+		return
+	}
+
+	sorted, err := sortModulesByDependencies(modules)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Building %d module(s) with up to %d in parallel...\n", len(sorted), *parallel)
+	results := buildWorkspaceModules(*folderPath, sorted, *parallel, *mode, quiet)
+
+	fmt.Println()
+	printBuildSummary(results)
+
+	for _, result := range results {
+		if result.status != moduleStatusOK {
+			os.Exit(1)
+		}
+	}
+}
+
+// buildWorkspaceModules builds the modules (given in dependency order). A module is started as soon as
+// all modules it requires are built successfully; it is skipped if one of them failed.
+func buildWorkspaceModules(rootPath string, modules []*workspaceModule, parallel int, mode string, quiet bool) []*moduleBuildResult {
+	results := make([]*moduleBuildResult, len(modules))
+	resultsByPath := make(map[string]*moduleBuildResult, len(modules))
+	finished := make(map[string]chan struct{}, len(modules))
+	for i, module := range modules {
+		results[i] = &moduleBuildResult{module: module}
+		resultsByPath[module.Path] = results[i]
+		finished[module.Path] = make(chan struct{})
+	}
+
+	semaphore := make(chan struct{}, parallel)
+	var outputMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, result := range results {
+		wg.Add(1)
+		go func(result *moduleBuildResult) {
+			defer wg.Done()
+			defer close(finished[result.module.Path])
+
+			// Wait for the required workspace modules
+			for _, dep := range result.module.Deps {
+				<-finished[dep]
+				if resultsByPath[dep].status != moduleStatusOK {
+					result.status = moduleStatusSkipped
+					result.err = fmt.Errorf("required module %s was not built", dep)
+				}
+			}
+
+			if result.status != moduleStatusSkipped {
+				semaphore <- struct{}{}
+				buildWorkspaceModule(rootPath, result, mode)
+				<-semaphore
+			}
+
+			outputMutex.Lock()
+			defer outputMutex.Unlock()
+			fmt.Printf("=== %s: %s (%s)\n", result.module.Folder, result.status, formatDuration(result.duration))
+			if !quiet || result.status == moduleStatusFailed {
+				_, _ = os.Stdout.Write(result.output)
+			}
+			if result.err != nil {
+				fmt.Println("Error:", result.err)
+			}
+		}(result)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// buildWorkspaceModule builds a single module and records the result.
+func buildWorkspaceModule(rootPath string, result *moduleBuildResult, mode string) {
+	folder := filepath.Join(rootPath, result.module.Folder)
+	cmd := moduleBuildCommand(folder, mode)
+	cmd.Dir = folder
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	result.duration = time.Since(start)
+	result.output = output
+	result.err = err
+	result.status = moduleStatusOK
+	if err != nil {
+		result.status = moduleStatusFailed
+	}
+}
+
+// moduleBuildCommand returns the command building the module in folder. In script mode the build script
+// of the module is used, if it exists.
+func moduleBuildCommand(folder, mode string) *exec.Cmd {
+	if mode == buildModeScript {
+		if runtime.GOOS == "windows" {
+			if fileExists(filepath.Join(folder, "build.bat")) {
+				return exec.Command("cmd", "/C", "build.bat")
+			}
+		} else if fileExists(filepath.Join(folder, "build.sh")) {
+			return exec.Command("bash", "build.sh")
+		}
+	}
+	return exec.Command("go", "build", "./...")
+}
+
+// printBuildSummary prints a table with the status and duration of every module.
+func printBuildSummary(results []*moduleBuildResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODULE\tPATH\tSTATUS\tDURATION")
+	var total time.Duration
+	failed := 0
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.module.Folder, result.module.Path, result.status, formatDuration(result.duration))
+		total += result.duration
+		if result.status != moduleStatusOK {
+			failed++
+		}
+	}
+	_ = writer.Flush()
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%d of %d module(s) failed or were skipped.\n", failed, len(results))
+	} else {
+		fmt.Printf("All %d module(s) built successfully (cumulated build time %s).\n", len(results), formatDuration(total))
+	}
+}
+
+// formatDuration formats a duration for the summary output.
+func formatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return "-"
+	}
+	return duration.Round(10 * time.Millisecond).String()
+}
+
+// fileExists reports whether filePath exists.
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// createWorkspaceBuildScripts creates build.sh and build.bat in the workspace root, which build all
+// modules of the workspace using "vasgotools work build".
+func createWorkspaceBuildScripts(folderPath string) error {
+	batchFilePath := filepath.Join(folderPath, "build.bat")
+	err1 := os.WriteFile(batchFilePath, []byte(workBuildBatTemplate), 0o600)
+	if err1 != nil {
+		err1 = fmt.Errorf("error creating build.bat: %w", err1)
+	}
+
+	scriptFilePath := filepath.Join(folderPath, "build.sh")
+	//nolint:gosec // G306: Script needs to be executable
+	err2 := os.WriteFile(scriptFilePath, []byte(workBuildShTemplate), 0o700) // Make the script executable
+	if err2 != nil {
+		err2 = fmt.Errorf("error creating build.sh: %w", err2)
+	}
+	return errors.Join(err1, err2)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// goModFile is the subset of the "go mod edit -json" output used by vasgotools.
+type goModFile struct {
+	Module struct {
+		Path string
+	}
+	Go      string
+	Require []struct {
+		Path     string
+		Version  string
+		Indirect bool
+	}
+	Replace []struct {
+		Old struct {
+			Path    string
+			Version string
+		}
+		New struct {
+			Path    string
+			Version string
+		}
+	}
+}
+
+// goWorkFile is the subset of the "go work edit -json" output used by vasgotools.
+type goWorkFile struct {
+	Go  string
+	Use []struct {
+		DiskPath string
+	}
+}
+
+// findGoModFolders walks rootPath and returns the relative paths of all folders containing a go.mod file.
+// vendor folders are skipped.
+func findGoModFolders(rootPath string) ([]string, error) {
+	var goModFolders []string
+
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip vendored code, it contains no workspace modules
+		if info.IsDir() && info.Name() == "vendor" && path != rootPath {
+			return filepath.SkipDir
+		}
+
+		// Check if the current item is a file named "go.mod"
+		if info.Name() == "go.mod" {
+			// Get the relative path of the folder containing go.mod
+			relativePath, err := filepath.Rel(rootPath, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			goModFolders = append(goModFolders, relativePath)
+		}
+		return nil
+	})
+
+	return goModFolders, err
+}
+
+// readGoWork reads the go.work file in rootPath using "go work edit -json".
+func readGoWork(rootPath string) (*goWorkFile, error) {
+	cmd := exec.Command("go", "work", "edit", "-json")
+	cmd.Dir = rootPath
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading go.work in %s: %w", rootPath, err)
+	}
+
+	var work goWorkFile
+	if err := json.Unmarshal(output, &work); err != nil {
+		return nil, fmt.Errorf("error parsing go.work in %s: %w", rootPath, err)
+	}
+	return &work, nil
+}
+
+// readGoMod reads the go.mod file in modulePath using "go mod edit -json".
+func readGoMod(modulePath string) (*goModFile, error) {
+	cmd := exec.Command("go", "mod", "edit", "-json")
+	cmd.Dir = modulePath
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading go.mod in %s: %w", modulePath, err)
+	}
+
+	var mod goModFile
+	if err := json.Unmarshal(output, &mod); err != nil {
+		return nil, fmt.Errorf("error parsing go.mod in %s: %w", modulePath, err)
+	}
+	return &mod, nil
+}
+
+// workspaceModuleFolders returns the relative paths of the modules of the workspace in rootPath.
+// The modules listed in go.work are used if it exists, otherwise the modules are discovered
+// the same way the "work" command does.
+func workspaceModuleFolders(rootPath string) ([]string, error) {
+	if !isWorkspace(rootPath) {
+		return findGoModFolders(rootPath)
+	}
+
+	work, err := readGoWork(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		folders = append(folders, filepath.Clean(filepath.FromSlash(use.DiskPath)))
+	}
+	return folders, nil
+}
+
+// isWorkspace reports whether folderPath contains a go.work file.
+func isWorkspace(folderPath string) bool {
+	_, err := os.Stat(filepath.Join(folderPath, "go.work"))
+	return err == nil
+}
+
+// mainPackageDirs returns the slash-separated folders of the commands (package main) of the module
+// in modulePath relative to the module ("." for the module root), sorted.
+func mainPackageDirs(modulePath string) ([]string, error) {
+	mod, err := readGoMod(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("go", "list", "-f", `{{if eq .Name "main"}}{{.ImportPath}}{{end}}`, "./...")
+	cmd.Dir = modulePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the packages of %s: %w", modulePath, err)
+	}
+
+	var dirs []string
+	for _, importPath := range strings.Fields(string(output)) {
+		if importPath == mod.Module.Path {
+			dirs = append(dirs, ".")
+		} else if dir, ok := strings.CutPrefix(importPath, mod.Module.Path+"/"); ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs, nil
+}
+
+// listedModule is the subset of the "go list -m -json" output used by vasgotools.
+type listedModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Dir      string
+	GoMod    string
+	Replace  *listedModule
+}
+
+// listModules returns the module graph of the module or workspace in folderPath using "go list -m -json all".
+func listModules(folderPath string) ([]listedModule, error) {
+	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd.Dir = folderPath
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running 'go list -m -json all' in %s: %w", folderPath, err)
+	}
+
+	// The output is a stream of JSON objects, not a JSON array
+	var modules []listedModule
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var module listedModule
+		if err := decoder.Decode(&module); err != nil {
+			return nil, fmt.Errorf("error parsing 'go list -m -json all' output: %w", err)
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// moduleDir returns the folder of module in the module cache, downloading the module if necessary.
+func moduleDir(folderPath string, module listedModule) (string, error) {
+	if module.Replace != nil {
+		module = *module.Replace
+	}
+	if module.Dir != "" {
+		return module.Dir, nil
+	}
+	if module.Version == "" {
+		return "", fmt.Errorf("module %s has neither a folder nor a version", module.Path)
+	}
+
+	//nolint:gosec // G204: Safe usage - module path and version are taken from the module graph
+	cmd := exec.Command("go", "mod", "download", "-json", module.Path+"@"+module.Version)
+	cmd.Dir = folderPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error downloading module %s@%s: %w", module.Path, module.Version, err)
+	}
+
+	var downloaded struct {
+		Dir   string
+		Error string
+	}
+	if err := json.Unmarshal(output, &downloaded); err != nil {
+		return "", fmt.Errorf("error parsing 'go mod download' output: %w", err)
+	}
+	if downloaded.Error != "" {
+		return "", fmt.Errorf("error downloading module %s@%s: %s", module.Path, module.Version, downloaded.Error)
+	}
+	return downloaded.Dir, nil
+}